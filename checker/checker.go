@@ -0,0 +1,42 @@
+package checker
+
+import (
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+	"fmt"
+	"math/big"
+)
+
+// Violation describes a constraint for which (L·a) * (R·a) != O·a
+type Violation struct {
+	Index  int
+	Left   *big.Int
+	Right  *big.Int
+	Output *big.Int
+	// product is L·a * R·a mod r
+	product *big.Int
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("constraint %d is not satisfied: L·a = %s, R·a = %s, O·a = %s (L·a * R·a = %s)", v.Index, v.Left, v.Right, v.Output, v.product)
+}
+
+// Check multiplies out L·a, R·a and O·a row by row modulo r, the scalar field order of the curve,
+// and returns every violated constraint. An error is returned when the witness size doesn't match the R1CS.
+func Check(r1csData r1cs.R1CSData, witnessData witness.WitnessData, r *big.Int) ([]Violation, error) {
+	La, Ra, Oa, err := r1csData.Eval(witnessData.Values(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]Violation, 0)
+	for i := range La {
+		product := new(big.Int).Mul(La[i], Ra[i])
+		product.Mod(product, r)
+		if product.Cmp(Oa[i]) != 0 {
+			violations = append(violations, Violation{Index: i, Left: La[i], Right: Ra[i], Output: Oa[i], product: product})
+		}
+	}
+
+	return violations, nil
+}
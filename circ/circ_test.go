@@ -0,0 +1,121 @@
+package circ_test
+
+import (
+	"errors"
+	"math/big"
+	"r1cs-zk-go/checker"
+	"r1cs-zk-go/circ"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+var r = ecc.BN254.ScalarField()
+
+// the example of the package documentation
+const exampleSrc = `// y = x^3 + x + 5
+public y;
+private x;
+let x2 = x * x;
+y === x2 * x + x + 5;
+`
+
+// TestCompileAndSolve compiles the example and checks the shape of its R1CS and that the solved witness
+// [1, y, x, x^2, x^3] satisfies it
+func TestCompileAndSolve(t *testing.T) {
+	circuit, err := circ.Compile(exampleSrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if circuit.R1CS.NbConstraints() != 3 || circuit.R1CS.NbVariables != 5 || circuit.R1CS.NbPublicInputs != 2 {
+		t.Fatalf("expected 3 constraints, 5 wires and 2 public inputs, got %d, %d and %d",
+			circuit.R1CS.NbConstraints(), circuit.R1CS.NbVariables, circuit.R1CS.NbPublicInputs)
+	}
+
+	witnessData, err := circuit.Solve(map[string]*big.Int{"x": big.NewInt(3), "y": big.NewInt(35)}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int64{1, 35, 3, 9, 27}
+	values := witnessData.Values()
+	if len(witnessData.PublicInputs) != 2 || len(values) != len(expected) {
+		t.Fatalf("expected 2 public inputs out of %d values, got %d out of %d", len(expected), len(witnessData.PublicInputs), len(values))
+	}
+	for i, value := range values {
+		if value.Cmp(big.NewInt(expected[i])) != 0 {
+			t.Errorf("wire %d: expected %d, got %s", i, expected[i], value)
+		}
+	}
+
+	violations, err := checker.Check(circuit.R1CS, witnessData, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("the solved witness violates %v", violations)
+	}
+}
+
+// TestSolveErrors checks that inputs breaking a constraint are reported at the operation that added it
+func TestSolveErrors(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		src    string
+		inputs map[string]*big.Int
+		pos    circ.Pos
+	}{
+		{"broken assertion", exampleSrc, map[string]*big.Int{"x": big.NewInt(3), "y": big.NewInt(36)}, circ.Pos{Line: 5, Column: 3}},
+		{"division by zero", "private a, b;\npublic q;\nq === a / b;", map[string]*big.Int{"a": big.NewInt(1), "b": big.NewInt(0), "q": big.NewInt(1)}, circ.Pos{Line: 3, Column: 9}},
+	} {
+		circuit, err := circ.Compile(c.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = circuit.Solve(c.inputs, r)
+		var circErr *circ.Error
+		if !errors.As(err, &circErr) {
+			t.Errorf("%s: expected a *circ.Error, got %v", c.name, err)
+			continue
+		}
+		if circErr.Pos != c.pos {
+			t.Errorf("%s: expected the error at %s, got %v", c.name, c.pos, err)
+		}
+	}
+}
+
+// TestCompileErrors checks that syntax and semantic errors point to their line and column, columns counting
+// characters rather than bytes
+func TestCompileErrors(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		src  string
+		pos  circ.Pos
+	}{
+		{"undeclared name", "public x;\nx === y;", circ.Pos{Line: 2, Column: 7}},
+		{"after a comment", "// y is missing\npublic x;\nx === y;", circ.Pos{Line: 3, Column: 7}},
+		{"missing semicolon", "public x\nprivate y;", circ.Pos{Line: 2, Column: 1}},
+		{"double equal", "public x;\nx == 1;", circ.Pos{Line: 2, Column: 3}},
+		{"assignment", "public x;\nx = 1;", circ.Pos{Line: 2, Column: 3}},
+		{"redeclared", "public x;\nlet x = 2;", circ.Pos{Line: 2, Column: 5}},
+		{"division by constant zero", "public x;\nx === 1 / 0;", circ.Pos{Line: 2, Column: 9}},
+		{"unclosed parenthesis", "public x;\nx === (x + 1;", circ.Pos{Line: 2, Column: 13}},
+		{"invalid number", "public x;\nx === 0xg;", circ.Pos{Line: 2, Column: 7}},
+		{"unexpected character", "public x;\n  x === 1 # 2;", circ.Pos{Line: 2, Column: 11}},
+		{"multibyte characters", "public é;\né === é @;", circ.Pos{Line: 2, Column: 9}},
+		{"missing expression", "public x;\nx === ;", circ.Pos{Line: 2, Column: 7}},
+	} {
+		_, err := circ.Compile(c.src)
+		var circErr *circ.Error
+		if !errors.As(err, &circErr) {
+			t.Errorf("%s: expected a *circ.Error, got %v", c.name, err)
+			continue
+		}
+		if circErr.Pos != c.pos {
+			t.Errorf("%s: expected the error at %s, got %v", c.name, c.pos, err)
+		}
+	}
+
+	if _, err := circ.Compile("public x;\nprivate y;"); err == nil {
+		t.Error("compiled a circuit without constraints")
+	}
+}
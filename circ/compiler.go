@@ -0,0 +1,191 @@
+// Package circ compiles .circ files, a small language for writing circuits without Go, to an R1CS with a
+// solver computing the witness from the values of the inputs:
+//
+//	// y = x^3 + x + 5
+//	public y;
+//	private x;
+//	let x2 = x * x;
+//	y === x2 * x + x + 5;
+//
+// Inputs are declared with public or private, possibly several at once (public a, b;), before they are used.
+// let names the value of an expression and a === b constrains two expressions to be equal. Expressions are
+// made of names, integer constants (decimal or 0x hex), + - * / and parentheses, with the usual precedence.
+// The compiler flattens them with the frontend package: sums and products by a constant stay linear
+// combinations, every other product becomes a multiplication gate and a division q = a / b adds the gate
+// q * b = a. Errors point to the line and column of the source.
+package circ
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/frontend"
+	"r1cs-zk-go/utils"
+	"r1cs-zk-go/witness"
+)
+
+// Circuit is a compiled .circ file
+type Circuit struct {
+	*frontend.Circuit
+	// positions holds the position of the operation that added each constraint
+	positions []Pos
+}
+
+// binding is a name in scope, an input or a let
+type binding struct {
+	pos   Pos
+	value frontend.Variable
+}
+
+type compiler struct {
+	builder   *frontend.Builder
+	scope     map[string]binding
+	positions []Pos
+}
+
+// Compile parses the source of a .circ file and returns its circuit, or the first error as an *Error
+func Compile(src string) (*Circuit, error) {
+	statements, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compiler{builder: frontend.NewBuilder(), scope: make(map[string]binding)}
+	for _, s := range statements {
+		if err := c.statement(s); err != nil {
+			return nil, err
+		}
+	}
+	if c.builder.NbConstraints() == 0 {
+		return nil, fmt.Errorf("the circuit has no constraints, add one with a === b")
+	}
+
+	circuit, err := c.builder.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Circuit{Circuit: circuit, positions: c.positions}, nil
+}
+
+// Solve computes the witness like frontend.Circuit.Solve, reporting the inputs that can't satisfy the circuit
+// at the operation that added the constraint
+func (c *Circuit) Solve(inputs map[string]*big.Int, r *big.Int) (witness.WitnessData, error) {
+	witnessData, err := c.Circuit.Solve(inputs, r)
+	var constraintErr *frontend.ConstraintError
+	if errors.As(err, &constraintErr) && constraintErr.Index < len(c.positions) {
+		return witness.WitnessData{}, errorf(c.positions[constraintErr.Index], "%v", err)
+	}
+	return witnessData, err
+}
+
+func (c *compiler) statement(s statement) error {
+	switch s := s.(type) {
+	case *declaration:
+		for _, name := range s.names {
+			if err := c.declare(name); err != nil {
+				return err
+			}
+			input := c.builder.Secret
+			if s.public {
+				input = c.builder.Public
+			}
+			c.scope[name.name] = binding{pos: name.pos, value: input(name.name)}
+		}
+
+	case *let:
+		value, err := c.expr(s.value)
+		if err != nil {
+			return err
+		}
+		if err := c.declare(s.name); err != nil {
+			return err
+		}
+		c.scope[s.name.name] = binding{pos: s.name.pos, value: value}
+
+	case *assertion:
+		left, err := c.expr(s.left)
+		if err != nil {
+			return err
+		}
+		right, err := c.expr(s.right)
+		if err != nil {
+			return err
+		}
+		c.builder.AssertIsEqual(left, right)
+		c.record(s.pos)
+	}
+	return nil
+}
+
+// declare checks that the name isn't in scope yet, inputs and lets can't be redefined
+func (c *compiler) declare(name *ident) error {
+	if previous, ok := c.scope[name.name]; ok {
+		return errorf(name.pos, "%s is already declared at %s", name.name, previous.pos)
+	}
+	return nil
+}
+
+// record attributes the constraints added since the last call to the operation at pos
+func (c *compiler) record(pos Pos) {
+	for len(c.positions) < c.builder.NbConstraints() {
+		c.positions = append(c.positions, pos)
+	}
+}
+
+func (c *compiler) expr(e expr) (frontend.Variable, error) {
+	switch e := e.(type) {
+	case *ident:
+		b, ok := c.scope[e.name]
+		if !ok {
+			return frontend.Variable{}, errorf(e.pos, "undeclared name %s, inputs are declared with public or private and values with let", e.name)
+		}
+		return b.value, nil
+
+	case *number:
+		value, err := utils.ParseValue([]byte(e.text))
+		if err != nil {
+			return frontend.Variable{}, errorf(e.pos, "invalid number %s, expected a decimal or 0x hex integer", e.text)
+		}
+		return c.builder.BigConstant(value), nil
+
+	case *negation:
+		x, err := c.expr(e.x)
+		if err != nil {
+			return frontend.Variable{}, err
+		}
+		return c.builder.Sub(c.builder.Constant(0), x), nil
+
+	case *binary:
+		left, err := c.expr(e.left)
+		if err != nil {
+			return frontend.Variable{}, err
+		}
+		right, err := c.expr(e.right)
+		if err != nil {
+			return frontend.Variable{}, err
+		}
+
+		var res frontend.Variable
+		switch e.op {
+		case tokenPlus:
+			res = c.builder.Add(left, right)
+		case tokenMinus:
+			res = c.builder.Sub(left, right)
+		case tokenStar:
+			res = c.builder.Mul(left, right)
+		case tokenSlash:
+			if n, ok := e.right.(*number); ok && isZero(n.text) {
+				return frontend.Variable{}, errorf(e.pos, "division by zero")
+			}
+			res = c.builder.Div(left, right)
+		}
+		c.record(e.pos)
+		return res, nil
+	}
+	return frontend.Variable{}, errorf(e.position(), "unexpected expression")
+}
+
+func isZero(text string) bool {
+	value, err := utils.ParseValue([]byte(text))
+	return err == nil && value.Sign() == 0
+}
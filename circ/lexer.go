@@ -0,0 +1,178 @@
+package circ
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pos is a position in the source, lines and columns start at 1 and columns count characters
+type Pos struct {
+	Line, Column int
+}
+
+func (pos Pos) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// Error is a syntax or semantic error of a .circ file, at the position it was found
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+func errorf(pos Pos, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenPublic
+	tokenPrivate
+	tokenLet
+	tokenAssign   // =
+	tokenEqual    // ===
+	tokenPlus
+	tokenMinus
+	tokenStar
+	tokenSlash
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenSemicolon
+)
+
+var keywords = map[string]tokenKind{
+	"public":  tokenPublic,
+	"private": tokenPrivate,
+	"let":     tokenLet,
+}
+
+var punctuation = map[rune]tokenKind{
+	'+': tokenPlus,
+	'-': tokenMinus,
+	'*': tokenStar,
+	'/': tokenSlash,
+	'(': tokenLParen,
+	')': tokenRParen,
+	',': tokenComma,
+	';': tokenSemicolon,
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos Pos
+}
+
+// String describes the token in error messages
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenIdent:
+		return fmt.Sprintf("identifier %q", t.text)
+	case tokenNumber:
+		return fmt.Sprintf("number %s", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// lexer splits the source into tokens, skipping whitespace and // comments
+type lexer struct {
+	src string
+	off int
+	pos Pos
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, pos: Pos{Line: 1, Column: 1}}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.off >= len(l.src) {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+	return r
+}
+
+func (l *lexer) nextRune() rune {
+	r, size := utf8.DecodeRuneInString(l.src[l.off:])
+	l.off += size
+	if r == '\n' {
+		l.pos.Line++
+		l.pos.Column = 1
+	} else {
+		l.pos.Column++
+	}
+	return r
+}
+
+// next returns the next token
+func (l *lexer) next() (token, error) {
+	l.skipSpaceAndComments()
+
+	pos, start := l.pos, l.off
+	r := l.peekRune()
+	switch {
+	case r == -1:
+		return token{kind: tokenEOF, pos: pos}, nil
+	case r == '_' || unicode.IsLetter(r):
+		for r := l.peekRune(); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r); r = l.peekRune() {
+			l.nextRune()
+		}
+		text := l.src[start:l.off]
+		if kind, ok := keywords[text]; ok {
+			return token{kind: kind, text: text, pos: pos}, nil
+		}
+		return token{kind: tokenIdent, text: text, pos: pos}, nil
+	case unicode.IsDigit(r):
+		// decimal or 0x hex, the value is parsed by the parser
+		for r := l.peekRune(); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r); r = l.peekRune() {
+			l.nextRune()
+		}
+		return token{kind: tokenNumber, text: l.src[start:l.off], pos: pos}, nil
+	case r == '=':
+		l.nextRune()
+		if l.peekRune() != '=' {
+			return token{kind: tokenAssign, text: "=", pos: pos}, nil
+		}
+		l.nextRune()
+		if l.peekRune() != '=' {
+			return token{}, errorf(pos, "unexpected '==', constraints are written a === b")
+		}
+		l.nextRune()
+		return token{kind: tokenEqual, text: "===", pos: pos}, nil
+	}
+
+	l.nextRune()
+	if kind, ok := punctuation[r]; ok {
+		return token{kind: kind, text: string(r), pos: pos}, nil
+	}
+	return token{}, errorf(pos, "unexpected character %q", r)
+}
+
+func (l *lexer) skipSpaceAndComments() {
+	for {
+		r := l.peekRune()
+		switch {
+		case r != -1 && unicode.IsSpace(r):
+			l.nextRune()
+		case r == '/' && l.off + 1 < len(l.src) && l.src[l.off + 1] == '/':
+			for r := l.peekRune(); r != -1 && r != '\n'; r = l.peekRune() {
+				l.nextRune()
+			}
+		default:
+			return
+		}
+	}
+}
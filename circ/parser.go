@@ -0,0 +1,261 @@
+package circ
+
+// statement is a line of the program ending with ';'
+type statement interface {
+	position() Pos
+}
+
+// declaration declares public or private inputs: public y, z;
+type declaration struct {
+	pos    Pos
+	public bool
+	names  []*ident
+}
+
+// let names the value of an expression: let x2 = x * x;
+type let struct {
+	pos   Pos
+	name  *ident
+	value expr
+}
+
+// assertion constrains two expressions to be equal: y === x * x;
+type assertion struct {
+	pos         Pos
+	left, right expr
+}
+
+func (s *declaration) position() Pos { return s.pos }
+func (s *let) position() Pos         { return s.pos }
+func (s *assertion) position() Pos   { return s.pos }
+
+// expr is an expression, its position is the one of the operator for binary expressions so that errors point
+// at the operation
+type expr interface {
+	position() Pos
+}
+
+type ident struct {
+	pos  Pos
+	name string
+}
+
+type number struct {
+	pos  Pos
+	text string
+}
+
+type binary struct {
+	pos         Pos
+	op          tokenKind
+	left, right expr
+}
+
+type negation struct {
+	pos Pos
+	x   expr
+}
+
+func (e *ident) position() Pos    { return e.pos }
+func (e *number) position() Pos   { return e.pos }
+func (e *binary) position() Pos   { return e.pos }
+func (e *negation) position() Pos { return e.pos }
+
+// parser is a recursive descent parser over the tokens of the lexer, with one token of lookahead:
+//
+//	program    := statement*
+//	statement  := ("public" | "private") ident ("," ident)* ";"
+//	            | "let" ident "=" expr ";"
+//	            | expr "===" expr ";"
+//	expr       := term (("+" | "-") term)*
+//	term       := unary (("*" | "/") unary)*
+//	unary      := "-" unary | primary
+//	primary    := number | ident | "(" expr ")"
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+// parse returns the statements of the source, or the first syntax error
+func parse(src string) ([]statement, error) {
+	p := &parser{lexer: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var statements []statement
+	for p.tok.kind != tokenEOF {
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+	return statements, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// expect consumes a token of the given kind, what describes it in the error
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.tok
+	if tok.kind != kind {
+		return token{}, errorf(tok.pos, "expected %s, got %s", what, tok)
+	}
+	return tok, p.advance()
+}
+
+func (p *parser) statement() (statement, error) {
+	start := p.tok
+	switch start.kind {
+	case tokenPublic, tokenPrivate:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		s := &declaration{pos: start.pos, public: start.kind == tokenPublic}
+		for {
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			s.names = append(s.names, name)
+			if p.tok.kind != tokenComma {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		return s, p.end()
+
+	case tokenLet:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenAssign, "'='"); err != nil {
+			return nil, err
+		}
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &let{pos: start.pos, name: name, value: value}, p.end()
+	}
+
+	left, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenAssign {
+		return nil, errorf(p.tok.pos, "unexpected '=', constraints are written a === b and values are named with let")
+	}
+	op, err := p.expect(tokenEqual, "'===' or an operator")
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &assertion{pos: op.pos, left: left, right: right}, p.end()
+}
+
+// end consumes the ';' ending a statement
+func (p *parser) end() error {
+	_, err := p.expect(tokenSemicolon, "';'")
+	return err
+}
+
+func (p *parser) ident() (*ident, error) {
+	tok, err := p.expect(tokenIdent, "a name")
+	if err != nil {
+		return nil, err
+	}
+	return &ident{pos: tok.pos, name: tok.text}, nil
+}
+
+func (p *parser) expr() (expr, error) {
+	return p.binary(p.term, tokenPlus, tokenMinus)
+}
+
+func (p *parser) term() (expr, error) {
+	return p.binary(p.unary, tokenStar, tokenSlash)
+}
+
+// binary parses operands separated by the given operators, which are left associative
+func (p *parser) binary(operand func() (expr, error), ops ...tokenKind) (expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(ops...) {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{pos: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) is(kinds ...tokenKind) bool {
+	for _, kind := range kinds {
+		if p.tok.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.tok.kind != tokenMinus {
+		return p.primary()
+	}
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &negation{pos: pos, x: x}, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenNumber:
+		return &number{pos: tok.pos, text: tok.text}, p.advance()
+	case tokenIdent:
+		return &ident{pos: tok.pos, name: tok.text}, p.advance()
+	case tokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRParen {
+			return nil, errorf(p.tok.pos, "expected ')' closing the '(' at %s, got %s", tok.pos, p.tok)
+		}
+		return x, p.advance()
+	}
+	return nil, errorf(tok.pos, "expected an expression, got %s", tok)
+}
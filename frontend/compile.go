@@ -0,0 +1,86 @@
+package frontend
+
+import (
+	"fmt"
+	"sort"
+	"r1cs-zk-go/r1cs"
+)
+
+// Circuit is a compiled circuit: its R1CS and what the solver needs to compute a witness for it
+type Circuit struct {
+	R1CS r1cs.R1CSData
+	// Public and Secret are the names of the inputs in the order of the witness: the constant 1 and the
+	// Public inputs are its public entries, the Secret inputs come next and the internal wires last
+	Public []string
+	Secret []string
+
+	wires []wire
+	ops   []string
+	// index maps the wires of the Builder to their index in the witness
+	index []int
+}
+
+// Compile returns the R1CS of the circuit, in which the wires are ordered as the witness, see Circuit
+func (b *Builder) Compile() (*Circuit, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	c := &Circuit{wires: b.wires, index: make([]int, len(b.wires))}
+	next := 0
+	for _, kind := range []wireKind{wireOne, wirePublic, wireSecret, wireInternal} {
+		for w, wire := range b.wires {
+			if wire.kind != kind {
+				continue
+			}
+			c.index[w] = next
+			next++
+			switch kind {
+			case wirePublic:
+				c.Public = append(c.Public, wire.name)
+			case wireSecret:
+				c.Secret = append(c.Secret, wire.name)
+			}
+		}
+	}
+
+	constraints := make([]r1cs.Constraint, len(b.constraints))
+	c.ops = make([]string, len(b.constraints))
+	for i, cs := range b.constraints {
+		var err error
+		if constraints[i].L, err = c.linearCombination(cs.a); err != nil {
+			return nil, err
+		}
+		if constraints[i].R, err = c.linearCombination(cs.b); err != nil {
+			return nil, err
+		}
+		if constraints[i].O, err = c.linearCombination(cs.c); err != nil {
+			return nil, err
+		}
+		c.ops[i] = cs.op
+	}
+
+	c.R1CS = r1cs.R1CSData{
+		Constraints:    constraints,
+		NbVariables:    len(b.wires),
+		NbPublicInputs: 1 + len(c.Public),
+	}
+	if err := c.R1CS.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// linearCombination returns the row of the variable, with the wires at their index in the witness
+func (c *Circuit) linearCombination(v Variable) (r1cs.LinearCombination, error) {
+	lc := make(r1cs.LinearCombination, len(v.terms))
+	for i, t := range v.terms {
+		if t.wire >= len(c.index) {
+			return nil, fmt.Errorf("a variable comes from another circuit")
+		}
+		lc[i] = r1cs.Term{Wire: c.index[t.wire], Coeff: t.coeff}
+	}
+	sort.Slice(lc, func(i, j int) bool { return lc[i].Wire < lc[j].Wire })
+	return lc, nil
+}
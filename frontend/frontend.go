@@ -0,0 +1,225 @@
+// Package frontend builds an R1CS from Go code instead of writing r1cs.json by hand. A circuit declares its
+// Public and Secret inputs on a Builder and combines them with Add, Sub, Mul and Div, which return new
+// Variables, and AssertIsEqual or AssertIsBoolean. Compile then returns the R1CS with a solver computing the
+// whole witness from the values of the inputs:
+//
+//	b := frontend.NewBuilder()
+//	x := b.Secret("x")
+//	out := b.Public("out")
+//	b.AssertIsEqual(out, b.Add(b.Mul(x, x, x), b.Mul(x, b.Constant(5)), b.Constant(5)))
+//	circuit, err := b.Compile()
+//	witnessData, err := circuit.Solve(map[string]*big.Int{"x": big.NewInt(5), "out": big.NewInt(155)}, ecc.BN254.ScalarField())
+//
+// Like r1cs.json, the R1CS doesn't depend on the curve: coefficients are integers and a division adds a wire
+// rather than multiplying by an inverse, so only the solver needs the scalar field.
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Variable is a linear combination of the wires of a Builder, with integer coefficients. Constants are
+// combinations of the constant wire alone, so additions and multiplications by a constant don't add constraints.
+type Variable struct {
+	// terms are sorted by wire and have nonzero coefficients
+	terms []term
+}
+
+type term struct {
+	wire  int
+	coeff *big.Int
+}
+
+type wireKind int
+
+const (
+	wireOne wireKind = iota
+	wirePublic
+	wireSecret
+	wireInternal
+)
+
+// wire is a wire of the circuit, internal wires being computed by the solver from the operation that created them
+type wire struct {
+	kind wireKind
+	name string
+	op   string
+	a, b Variable
+	// constraint is the index of the constraint the operation added
+	constraint int
+}
+
+// constraint is a * b = c, op says which call added it for error messages
+type constraint struct {
+	a, b, c Variable
+	op      string
+}
+
+// Builder records the wires and constraints of a circuit. Wire 0 is the constant 1. Like the decoders of the
+// keys, it keeps the first error, which Compile returns.
+type Builder struct {
+	wires       []wire
+	constraints []constraint
+	names       map[string]bool
+	err         error
+}
+
+// NewBuilder returns a Builder for a new circuit
+func NewBuilder() *Builder {
+	return &Builder{
+		wires: []wire{{kind: wireOne}},
+		names: make(map[string]bool),
+	}
+}
+
+// Public declares a public input, given to the verifier along with the proof
+func (b *Builder) Public(name string) Variable {
+	return b.input(wirePublic, name)
+}
+
+// Secret declares a private input, only known to the prover
+func (b *Builder) Secret(name string) Variable {
+	return b.input(wireSecret, name)
+}
+
+func (b *Builder) input(kind wireKind, name string) Variable {
+	if b.err == nil && name == "" {
+		b.err = fmt.Errorf("inputs must have a name")
+	}
+	if b.err == nil && b.names[name] {
+		b.err = fmt.Errorf("input %q is declared twice", name)
+	}
+	b.names[name] = true
+	b.wires = append(b.wires, wire{kind: kind, name: name})
+	return b.wireVariable(len(b.wires) - 1)
+}
+
+// Constant returns the constant value
+func (b *Builder) Constant(value int64) Variable {
+	return b.BigConstant(big.NewInt(value))
+}
+
+// BigConstant returns the constant value, which may be negative or larger than the scalar field
+func (b *Builder) BigConstant(value *big.Int) Variable {
+	if value.Sign() == 0 {
+		return Variable{}
+	}
+	return Variable{terms: []term{{wire: 0, coeff: new(big.Int).Set(value)}}}
+}
+
+// Add returns a + c + others...
+func (b *Builder) Add(a, c Variable, others ...Variable) Variable {
+	res := linearCombination(a, c, 1)
+	for _, v := range others {
+		res = linearCombination(res, v, 1)
+	}
+	return res
+}
+
+// Sub returns a - c
+func (b *Builder) Sub(a, c Variable) Variable {
+	return linearCombination(a, c, -1)
+}
+
+// Mul returns a * c * others..., every product of two variables that aren't constants adds a wire and a constraint
+func (b *Builder) Mul(a, c Variable, others ...Variable) Variable {
+	res := b.mul(a, c)
+	for _, v := range others {
+		res = b.mul(res, v)
+	}
+	return res
+}
+
+func (b *Builder) mul(a, c Variable) Variable {
+	if k, ok := a.constant(); ok {
+		return c.scale(k)
+	}
+	if k, ok := c.constant(); ok {
+		return a.scale(k)
+	}
+
+	res := b.internal("Mul", a, c)
+	b.constraints = append(b.constraints, constraint{a: a, b: c, c: res, op: "Mul"})
+	return res
+}
+
+// Div returns a / c. It adds a wire q and the constraint q * c = a, the solver fails when c is zero.
+func (b *Builder) Div(a, c Variable) Variable {
+	res := b.internal("Div", a, c)
+	b.constraints = append(b.constraints, constraint{a: res, b: c, c: a, op: "Div"})
+	return res
+}
+
+// AssertIsEqual constrains a to equal c: a * 1 = c
+func (b *Builder) AssertIsEqual(a, c Variable) {
+	b.constraints = append(b.constraints, constraint{a: a, b: b.Constant(1), c: c, op: "AssertIsEqual"})
+}
+
+// AssertIsBoolean constrains a to be 0 or 1: a * (1 - a) = 0
+func (b *Builder) AssertIsBoolean(a Variable) {
+	b.constraints = append(b.constraints, constraint{a: a, b: b.Sub(b.Constant(1), a), op: "AssertIsBoolean"})
+}
+
+// NbConstraints returns the number of constraints added so far, the next one having this index in the R1CS
+func (b *Builder) NbConstraints() int {
+	return len(b.constraints)
+}
+
+// internal adds a wire the solver computes with op from a and c, before the constraint of op is added
+func (b *Builder) internal(op string, a, c Variable) Variable {
+	b.wires = append(b.wires, wire{kind: wireInternal, op: op, a: a, b: c, constraint: len(b.constraints)})
+	return b.wireVariable(len(b.wires) - 1)
+}
+
+func (b *Builder) wireVariable(w int) Variable {
+	return Variable{terms: []term{{wire: w, coeff: big.NewInt(1)}}}
+}
+
+// constant returns the value of a variable that only depends on the constant wire
+func (v Variable) constant() (*big.Int, bool) {
+	switch {
+	case len(v.terms) == 0:
+		return new(big.Int), true
+	case len(v.terms) == 1 && v.terms[0].wire == 0:
+		return v.terms[0].coeff, true
+	}
+	return nil, false
+}
+
+func (v Variable) scale(k *big.Int) Variable {
+	if k.Sign() == 0 {
+		return Variable{}
+	}
+	res := Variable{terms: make([]term, len(v.terms))}
+	for i, t := range v.terms {
+		res.terms[i] = term{wire: t.wire, coeff: new(big.Int).Mul(t.coeff, k)}
+	}
+	return res
+}
+
+// linearCombination returns a + sign * c, merging the terms of the same wire
+func linearCombination(a, c Variable, sign int64) Variable {
+	coeffs := make(map[int]*big.Int, len(a.terms) + len(c.terms))
+	for _, t := range a.terms {
+		coeffs[t.wire] = new(big.Int).Set(t.coeff)
+	}
+	for _, t := range c.terms {
+		coeff, ok := coeffs[t.wire]
+		if !ok {
+			coeff = new(big.Int)
+			coeffs[t.wire] = coeff
+		}
+		coeff.Add(coeff, new(big.Int).Mul(t.coeff, big.NewInt(sign)))
+	}
+
+	var res Variable
+	for w, coeff := range coeffs {
+		if coeff.Sign() != 0 {
+			res.terms = append(res.terms, term{wire: w, coeff: coeff})
+		}
+	}
+	sort.Slice(res.terms, func(i, j int) bool { return res.terms[i].wire < res.terms[j].wire })
+	return res
+}
@@ -0,0 +1,171 @@
+package frontend_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/checker"
+	"r1cs-zk-go/frontend"
+	"r1cs-zk-go/r1cs"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+var r = ecc.BN254.ScalarField()
+
+// exampleCircuit is the x^3 + 5x + 5 = out example of the package documentation
+func exampleCircuit(t *testing.T) *frontend.Circuit {
+	b := frontend.NewBuilder()
+	x := b.Secret("x")
+	out := b.Public("out")
+	b.AssertIsEqual(out, b.Add(b.Mul(x, x, x), b.Mul(x, b.Constant(5)), b.Constant(5)))
+	circuit, err := b.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return circuit
+}
+
+// TestCompile checks the R1CS of the example: the wires are ordered 1, out, x, x^2, x^3, the products by a
+// constant are linear combinations and only the two products of x add constraints
+func TestCompile(t *testing.T) {
+	circuit := exampleCircuit(t)
+	if circuit.R1CS.NbVariables != 5 || circuit.R1CS.NbPublicInputs != 2 {
+		t.Fatalf("expected 5 wires and 2 public inputs, got %d and %d", circuit.R1CS.NbVariables, circuit.R1CS.NbPublicInputs)
+	}
+	if strings.Join(circuit.Public, ",") != "out" || strings.Join(circuit.Secret, ",") != "x" {
+		t.Fatalf("expected the inputs out and x, got %v and %v", circuit.Public, circuit.Secret)
+	}
+
+	expected := []string{
+		"1·w2 * 1·w2 = 1·w3",
+		"1·w3 * 1·w2 = 1·w4",
+		"1·w1 * 1·w0 = 5·w0 + 5·w2 + 1·w4",
+	}
+	if len(circuit.R1CS.Constraints) != len(expected) {
+		t.Fatalf("expected %d constraints, got %d", len(expected), len(circuit.R1CS.Constraints))
+	}
+	for i, c := range circuit.R1CS.Constraints {
+		if got := fmt.Sprintf("%s * %s = %s", row(c.L), row(c.R), row(c.O)); got != expected[i] {
+			t.Errorf("constraint %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+}
+
+// TestSolve checks that the solved witness is [1, out, x, x^2, x^3] and satisfies the R1CS
+func TestSolve(t *testing.T) {
+	circuit := exampleCircuit(t)
+	witnessData, err := circuit.Solve(map[string]*big.Int{"x": big.NewInt(5), "out": big.NewInt(155)}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int64{1, 155, 5, 25, 125}
+	values := witnessData.Values()
+	if len(witnessData.PublicInputs) != 2 || len(values) != len(expected) {
+		t.Fatalf("expected 2 public inputs out of %d values, got %d out of %d", len(expected), len(witnessData.PublicInputs), len(values))
+	}
+	for i, value := range values {
+		if value.Cmp(big.NewInt(expected[i])) != 0 {
+			t.Errorf("wire %d: expected %d, got %s", i, expected[i], value)
+		}
+	}
+
+	violations, err := checker.Check(circuit.R1CS, witnessData, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("the solved witness violates %v", violations)
+	}
+}
+
+// TestSolveErrors checks that a broken assertion and a division by zero are reported at their constraint, and
+// that unknown or missing inputs are errors
+func TestSolveErrors(t *testing.T) {
+	b := frontend.NewBuilder()
+	a, c := b.Secret("a"), b.Secret("c")
+	q := b.Public("q")
+	bit := b.Public("bit")
+	b.AssertIsEqual(q, b.Div(a, c))
+	b.AssertIsBoolean(bit)
+	circuit, err := b.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := func(a, c, q, bit int64) map[string]*big.Int {
+		return map[string]*big.Int{"a": big.NewInt(a), "c": big.NewInt(c), "q": big.NewInt(q), "bit": big.NewInt(bit)}
+	}
+	witnessData, err := circuit.Solve(inputs(6, 3, 2, 1), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if violations, err := checker.Check(circuit.R1CS, witnessData, r); err != nil || len(violations) != 0 {
+		t.Fatalf("the solved witness violates %v: %v", violations, err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		inputs map[string]*big.Int
+		index  int
+		op     string
+	}{
+		{"division by zero", inputs(6, 0, 2, 1), 0, "Div"},
+		{"wrong quotient", inputs(6, 3, 3, 1), 1, "AssertIsEqual"},
+		{"not a boolean", inputs(6, 3, 2, 2), 2, "AssertIsBoolean"},
+	} {
+		_, err := circuit.Solve(c.inputs, r)
+		var constraintErr *frontend.ConstraintError
+		if !errors.As(err, &constraintErr) {
+			t.Errorf("%s: expected a ConstraintError, got %v", c.name, err)
+			continue
+		}
+		if constraintErr.Index != c.index || constraintErr.Op != c.op {
+			t.Errorf("%s: expected constraint %d (%s), got %v", c.name, c.index, c.op, err)
+		}
+	}
+
+	unknown := inputs(6, 3, 2, 1)
+	unknown["d"] = big.NewInt(1)
+	if _, err := circuit.Solve(unknown, r); err == nil {
+		t.Error("solved with an unknown input")
+	}
+	missing := inputs(6, 3, 2, 1)
+	delete(missing, "c")
+	if _, err := circuit.Solve(missing, r); err == nil {
+		t.Error("solved without an input")
+	}
+}
+
+// TestBuilderErrors checks that the first error of the Builder is returned by Compile
+func TestBuilderErrors(t *testing.T) {
+	for name, build := range map[string]func(b *frontend.Builder){
+		"unnamed input": func(b *frontend.Builder) { b.AssertIsBoolean(b.Secret("")) },
+		"input declared twice": func(b *frontend.Builder) {
+			b.AssertIsEqual(b.Public("x"), b.Secret("x"))
+		},
+		"variable of another circuit": func(b *frontend.Builder) {
+			other := frontend.NewBuilder()
+			y := other.Mul(other.Secret("a"), other.Secret("b"))
+			b.AssertIsEqual(b.Public("x"), y)
+		},
+	} {
+		b := frontend.NewBuilder()
+		build(b)
+		if _, err := b.Compile(); err == nil {
+			t.Errorf("%s: the circuit compiled", name)
+		}
+	}
+}
+
+// row writes a linear combination as 5·w0 + 1·w4
+func row(lc r1cs.LinearCombination) string {
+	terms := make([]string, len(lc))
+	for i, t := range lc {
+		terms[i] = fmt.Sprintf("%s·w%d", t.Coeff, t.Wire)
+	}
+	return strings.Join(terms, " + ")
+}
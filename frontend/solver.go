@@ -0,0 +1,96 @@
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/checker"
+	"r1cs-zk-go/witness"
+)
+
+// ConstraintError reports the constraint the inputs can't satisfy, Index being its row in the R1CS and Op the
+// call that added it
+type ConstraintError struct {
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("constraint %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// Solve computes the whole witness from the values of the inputs, keyed by name, modulo r, the scalar field
+// order of the curve the proof will be made on. Internal wires are computed in the order they were created,
+// then every constraint is checked so that an assertion the inputs break is reported here rather than by the
+// prover. Both a division by zero and a broken assertion are reported as a ConstraintError.
+func (c *Circuit) Solve(inputs map[string]*big.Int, r *big.Int) (witness.WitnessData, error) {
+	declared := make(map[string]bool, len(c.Public) + len(c.Secret))
+	for _, name := range append(append([]string(nil), c.Public...), c.Secret...) {
+		declared[name] = true
+	}
+	for name := range inputs {
+		if !declared[name] {
+			return witness.WitnessData{}, fmt.Errorf("unknown input %q", name)
+		}
+	}
+
+	values := make([]*big.Int, len(c.wires))
+	for w, wire := range c.wires {
+		switch wire.kind {
+		case wireOne:
+			values[w] = big.NewInt(1)
+		case wirePublic, wireSecret:
+			value, ok := inputs[wire.name]
+			if !ok {
+				return witness.WitnessData{}, fmt.Errorf("missing value for input %q", wire.name)
+			}
+			values[w] = new(big.Int).Mod(value, r)
+		case wireInternal:
+			a, b := eval(wire.a, values, r), eval(wire.b, values, r)
+			switch wire.op {
+			case "Mul":
+				values[w] = a.Mul(a, b).Mod(a, r)
+			case "Div":
+				if b.Sign() == 0 {
+					return witness.WitnessData{}, &ConstraintError{Index: wire.constraint, Op: wire.op, Err: fmt.Errorf("division by zero")}
+				}
+				b.ModInverse(b, r)
+				values[w] = a.Mul(a, b).Mod(a, r)
+			}
+		}
+	}
+
+	vector := make([]*big.Int, len(values))
+	for w, value := range values {
+		vector[c.index[w]] = value
+	}
+	nbPublic := c.R1CS.NbPublicInputs
+	witnessData := witness.WitnessData{PublicInputs: vector[:nbPublic], PrivateInputs: vector[nbPublic:]}
+
+	violations, err := checker.Check(c.R1CS, witnessData, r)
+	if err != nil {
+		return witness.WitnessData{}, err
+	}
+	if len(violations) > 0 {
+		v := violations[0]
+		err := fmt.Errorf("the inputs don't satisfy it: L·a = %s, R·a = %s, O·a = %s", v.Left, v.Right, v.Output)
+		return witness.WitnessData{}, &ConstraintError{Index: v.Index, Op: c.ops[v.Index], Err: err}
+	}
+
+	return witnessData, nil
+}
+
+// eval returns the value of the variable modulo r
+func eval(v Variable, values []*big.Int, r *big.Int) *big.Int {
+	sum := new(big.Int)
+	var tmp big.Int
+	for _, t := range v.terms {
+		tmp.Mul(t.coeff, values[t.wire])
+		sum.Add(sum, &tmp)
+	}
+	return sum.Mod(sum, r)
+}
@@ -2,13 +2,9 @@ module r1cs-zk-go
 
 go 1.23.6
 
-require (
-	github.com/consensys/gnark-crypto v0.18.0
-	gitlab.com/oelmekki/matrix v0.0.0-20230709100531-36fba5ce31d0
-)
+require github.com/consensys/gnark-crypto v0.18.0
 
 require (
 	github.com/bits-and-blooms/bitset v1.20.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-
 )
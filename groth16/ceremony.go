@@ -0,0 +1,188 @@
+package groth16
+
+import (
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"io"
+	"r1cs-zk-go/groth16/bls12-377"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/groth16/bn254"
+	"r1cs-zk-go/groth16/bw6-761"
+)
+
+// Phase1 is the state of a powers of tau ceremony of one of the backends, *bn254.Phase1 for instance.
+// Participants take turns to Contribute, each one running locally on the file left by the previous one,
+// then a Beacon closes the ceremony. Anyone can Verify the whole transcript.
+type Phase1 interface {
+	Curve() ecc.ID
+	// Size is the size of the largest FFT domain the phase 1 can serve
+	Size() int
+	NbContributions() int
+	// Contribute multiplies the points by fresh secrets, which are wiped before returning
+	Contribute() error
+	// Beacon adds the last contribution, whose secrets are derived from the beacon hashed 2^iterations times
+	Beacon(beacon []byte, iterations int) error
+	Verify() error
+	// VerifyPowers only checks that the points are powers of the same secrets, for a phase 1 whose transcript
+	// was verified elsewhere, e.g. a snarkjs .ptau file
+	VerifyPowers() error
+	// Hash is the hash of the transcript, published by every contributor to attest their contribution
+	Hash() []byte
+}
+
+// NewPhase1 starts a powers of tau ceremony on the given curve for FFT domains of up to 2^power elements
+func NewPhase1(curveID ecc.ID, power int) (Phase1, error) {
+	switch curveID {
+	case ecc.BN254:
+		return result[Phase1](bn254.NewPhase1(power))
+	case ecc.BLS12_377:
+		return result[Phase1](bls12377.NewPhase1(power))
+	case ecc.BLS12_381:
+		return result[Phase1](bls12381.NewPhase1(power))
+	case ecc.BW6_761:
+		return result[Phase1](bw6761.NewPhase1(power))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// ReadPhase1 parses a JSON or binary phase 1, on the curve recorded in it, and checks that its points are valid.
+// Its transcript is only checked by Verify.
+func ReadPhase1(r io.Reader) (Phase1, error) {
+	data, curveID, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curveID {
+	case ecc.BN254:
+		return result[Phase1](bn254.ReadPhase1(data))
+	case ecc.BLS12_377:
+		return result[Phase1](bls12377.ReadPhase1(data))
+	case ecc.BLS12_381:
+		return result[Phase1](bls12381.ReadPhase1(data))
+	case ecc.BW6_761:
+		return result[Phase1](bw6761.ReadPhase1(data))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// WritePhase1 writes the phase 1 in the given format
+func WritePhase1(w io.Writer, p Phase1, format Format) error {
+	switch p := p.(type) {
+	case *bn254.Phase1:
+		return bn254.WritePhase1(w, p, format)
+	case *bls12377.Phase1:
+		return bls12377.WritePhase1(w, p, format)
+	case *bls12381.Phase1:
+		return bls12381.WritePhase1(w, p, format)
+	case *bw6761.Phase1:
+		return bw6761.WritePhase1(w, p, format)
+	}
+	return unsupportedType(p)
+}
+
+// Phase2 is the state of the circuit specific ceremony of one of the backends, *bn254.Phase2 for instance.
+// NewPhase2 derives the keys of an R1CS from a phase 1, then it goes like the phase 1 for the secret teta (delta):
+// participants Contribute, a Beacon closes it and VerifyPhase2 checks the whole transcript. Phase2Keys returns
+// the keys, many circuits can be set up from the same phase 1.
+type Phase2 interface {
+	Curve() ecc.ID
+	NbContributions() int
+	// Contribute multiplies teta by a fresh secret, which is wiped before returning
+	Contribute() error
+	// Beacon adds the last contribution, whose secret is derived from the beacon hashed 2^iterations times
+	Beacon(beacon []byte, iterations int) error
+	// Hash is the hash of the transcript, published by every contributor to attest their contribution
+	Hash() []byte
+}
+
+// NewPhase2 derives the keys of the R1CS from the phase 1, on its curve, without redoing the powers of tau.
+// The phase 1 should have been verified.
+func NewPhase2(p1 Phase1, r1csData R1CS) (Phase2, error) {
+	switch p1 := p1.(type) {
+	case *bn254.Phase1:
+		return result[Phase2](bn254.NewPhase2(p1, r1csData))
+	case *bls12377.Phase1:
+		return result[Phase2](bls12377.NewPhase2(p1, r1csData))
+	case *bls12381.Phase1:
+		return result[Phase2](bls12381.NewPhase2(p1, r1csData))
+	case *bw6761.Phase1:
+		return result[Phase2](bw6761.NewPhase2(p1, r1csData))
+	}
+	return nil, unsupportedType(p1)
+}
+
+// VerifyPhase2 checks that the phase 2 was derived from the phase 1 and from the R1CS, and every contribution.
+// The phase 1 must have been verified.
+func VerifyPhase2(p2 Phase2, p1 Phase1, r1csData R1CS) error {
+	if p2.Curve() != p1.Curve() {
+		return fmt.Errorf("the phase 2 was made on %s but the phase 1 on %s", p2.Curve(), p1.Curve())
+	}
+
+	switch p2 := p2.(type) {
+	case *bn254.Phase2:
+		return p2.Verify(p1.(*bn254.Phase1), r1csData)
+	case *bls12377.Phase2:
+		return p2.Verify(p1.(*bls12377.Phase1), r1csData)
+	case *bls12381.Phase2:
+		return p2.Verify(p1.(*bls12381.Phase1), r1csData)
+	case *bw6761.Phase2:
+		return p2.Verify(p1.(*bw6761.Phase1), r1csData)
+	}
+	return unsupportedType(p2)
+}
+
+// Phase2Keys returns the proving and verifying keys of the phase 2. They can only be trusted once the phase 2
+// has at least one contribution and VerifyPhase2 accepts it.
+func Phase2Keys(p2 Phase2) (ProvingKey, VerifyingKey, error) {
+	switch p2 := p2.(type) {
+	case *bn254.Phase2:
+		pk, vk := p2.Keys()
+		return pk, vk, nil
+	case *bls12377.Phase2:
+		pk, vk := p2.Keys()
+		return pk, vk, nil
+	case *bls12381.Phase2:
+		pk, vk := p2.Keys()
+		return pk, vk, nil
+	case *bw6761.Phase2:
+		pk, vk := p2.Keys()
+		return pk, vk, nil
+	}
+	return nil, nil, unsupportedType(p2)
+}
+
+// ReadPhase2 parses a JSON or binary phase 2, see ReadPhase1
+func ReadPhase2(r io.Reader) (Phase2, error) {
+	data, curveID, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curveID {
+	case ecc.BN254:
+		return result[Phase2](bn254.ReadPhase2(data))
+	case ecc.BLS12_377:
+		return result[Phase2](bls12377.ReadPhase2(data))
+	case ecc.BLS12_381:
+		return result[Phase2](bls12381.ReadPhase2(data))
+	case ecc.BW6_761:
+		return result[Phase2](bw6761.ReadPhase2(data))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// WritePhase2 writes the phase 2 in the given format
+func WritePhase2(w io.Writer, p Phase2, format Format) error {
+	switch p := p.(type) {
+	case *bn254.Phase2:
+		return bn254.WritePhase2(w, p, format)
+	case *bls12377.Phase2:
+		return bls12377.WritePhase2(w, p, format)
+	case *bls12381.Phase2:
+		return bls12381.WritePhase2(w, p, format)
+	case *bw6761.Phase2:
+		return bw6761.WritePhase2(w, p, format)
+	}
+	return unsupportedType(p)
+}
@@ -0,0 +1,287 @@
+// Package groth16 is the library entry point: it runs the trusted setup, proves and verifies on in-memory
+// values and never panics or exits on bad input. The CLI in main.go is a thin wrapper over it.
+//
+// Keys and proofs can be made on any of the Curves. The work is done by the backend of their curve, in
+// groth16/bn254, groth16/bls12-377, groth16/bls12-381 and groth16/bw6-761, and keys and proofs of
+// different curves can't be mixed. R1CS and witnesses don't depend on the curve.
+package groth16
+
+//go:generate go run ../internal/generator
+
+import (
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-377"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/groth16/bn254"
+	"r1cs-zk-go/groth16/bw6-761"
+	"r1cs-zk-go/keys"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+)
+
+// ProvingKey is the proving key of one of the backends, bn254.ProvingKey for instance
+type ProvingKey interface {
+	Curve() ecc.ID
+}
+
+// VerifyingKey is the verifying key of one of the backends, see ProvingKey
+type VerifyingKey interface {
+	Curve() ecc.ID
+}
+
+// Proof is the proof of one of the backends, see ProvingKey
+type Proof interface {
+	Curve() ecc.ID
+}
+
+type (
+	R1CS    = r1cs.R1CSData
+	Witness = witness.WitnessData
+)
+
+// BatchItem is a proof along with its public inputs, see VerifyBatch
+type BatchItem struct {
+	Proof        Proof
+	PublicInputs []*big.Int
+}
+
+// Curves are the supported curves
+var Curves = keys.Curves
+
+// DefaultCurve is the curve of keys and proofs written before the curve was recorded in them
+const DefaultCurve = keys.DefaultCurve
+
+// ParseCurve returns the supported curve with the given name, e.g. "bn254" or "bls12-381"
+func ParseCurve(name string) (ecc.ID, error) {
+	return keys.ParseCurve(name)
+}
+
+// Setup runs the trusted setup for the R1CS on the given curve
+func Setup(curveID ecc.ID, r1csData R1CS) (ProvingKey, VerifyingKey, error) {
+	switch curveID {
+	case ecc.BN254:
+		return keyPair(bn254.Setup(r1csData))
+	case ecc.BLS12_377:
+		return keyPair(bls12377.Setup(r1csData))
+	case ecc.BLS12_381:
+		return keyPair(bls12381.Setup(r1csData))
+	case ecc.BW6_761:
+		return keyPair(bw6761.Setup(r1csData))
+	}
+	return nil, nil, unsupportedCurve(curveID)
+}
+
+// Prove generates a proof for a witness satisfying the R1CS, on the curve of the proving key.
+// The proving key can be given by value or by pointer.
+func Prove(pk ProvingKey, r1csData R1CS, witnessData Witness) (Proof, error) {
+	pk, err := provingKeyValue(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pk := pk.(type) {
+	case bn254.ProvingKey:
+		return result[Proof](bn254.Prove(pk, r1csData, witnessData))
+	case bls12377.ProvingKey:
+		return result[Proof](bls12377.Prove(pk, r1csData, witnessData))
+	case bls12381.ProvingKey:
+		return result[Proof](bls12381.Prove(pk, r1csData, witnessData))
+	case bw6761.ProvingKey:
+		return result[Proof](bw6761.Prove(pk, r1csData, witnessData))
+	}
+	return nil, unsupportedType(pk)
+}
+
+// Verify checks the proof against the public inputs, which start with the constant 1.
+// The proof must have been made on the curve of the verifying key, both can be given by value or by pointer.
+func Verify(vk VerifyingKey, proof Proof, publicInputs []*big.Int) (bool, error) {
+	vk, err := verifyingKeyValue(vk)
+	if err != nil {
+		return false, err
+	}
+	proof, err = proofValue(proof)
+	if err != nil {
+		return false, err
+	}
+	if err := sameCurve(vk, proof); err != nil {
+		return false, err
+	}
+
+	switch vk := vk.(type) {
+	case bn254.VerifyingKey:
+		if proof, ok := proof.(bn254.Proof); ok {
+			return bn254.Verify(vk, proof, bn254.Elements(publicInputs))
+		}
+	case bls12377.VerifyingKey:
+		if proof, ok := proof.(bls12377.Proof); ok {
+			return bls12377.Verify(vk, proof, bls12377.Elements(publicInputs))
+		}
+	case bls12381.VerifyingKey:
+		if proof, ok := proof.(bls12381.Proof); ok {
+			return bls12381.Verify(vk, proof, bls12381.Elements(publicInputs))
+		}
+	case bw6761.VerifyingKey:
+		if proof, ok := proof.(bw6761.Proof); ok {
+			return bw6761.Verify(vk, proof, bw6761.Elements(publicInputs))
+		}
+	default:
+		return false, unsupportedType(vk)
+	}
+	return false, unsupportedType(proof)
+}
+
+// VerifyBatch verifies many proofs with a single multi-pairing and returns the indices of the invalid ones.
+// Every proof must have been made on the curve of the verifying key.
+func VerifyBatch(vk VerifyingKey, items []BatchItem) ([]int, error) {
+	vk, err := verifyingKeyValue(vk)
+	if err != nil {
+		return nil, err
+	}
+	proofs := make([]Proof, len(items))
+	for i := range items {
+		proof, err := proofValue(items[i].Proof)
+		if err == nil {
+			err = sameCurve(vk, proof)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("proof %d: %v", i, err)
+		}
+		proofs[i] = proof
+	}
+
+	switch vk := vk.(type) {
+	case bn254.VerifyingKey:
+		batch := make([]bn254.BatchItem, len(items))
+		for i, item := range items {
+			proof, ok := proofs[i].(bn254.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: %v", i, unsupportedType(proofs[i]))
+			}
+			batch[i] = bn254.BatchItem{Proof: proof, PublicInputs: bn254.Elements(item.PublicInputs)}
+		}
+		return bn254.VerifyBatch(vk, batch)
+	case bls12377.VerifyingKey:
+		batch := make([]bls12377.BatchItem, len(items))
+		for i, item := range items {
+			proof, ok := proofs[i].(bls12377.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: %v", i, unsupportedType(proofs[i]))
+			}
+			batch[i] = bls12377.BatchItem{Proof: proof, PublicInputs: bls12377.Elements(item.PublicInputs)}
+		}
+		return bls12377.VerifyBatch(vk, batch)
+	case bls12381.VerifyingKey:
+		batch := make([]bls12381.BatchItem, len(items))
+		for i, item := range items {
+			proof, ok := proofs[i].(bls12381.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: %v", i, unsupportedType(proofs[i]))
+			}
+			batch[i] = bls12381.BatchItem{Proof: proof, PublicInputs: bls12381.Elements(item.PublicInputs)}
+		}
+		return bls12381.VerifyBatch(vk, batch)
+	case bw6761.VerifyingKey:
+		batch := make([]bw6761.BatchItem, len(items))
+		for i, item := range items {
+			proof, ok := proofs[i].(bw6761.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: %v", i, unsupportedType(proofs[i]))
+			}
+			batch[i] = bw6761.BatchItem{Proof: proof, PublicInputs: bw6761.Elements(item.PublicInputs)}
+		}
+		return bw6761.VerifyBatch(vk, batch)
+	}
+	return nil, unsupportedType(vk)
+}
+
+// proofValue returns the proof of a backend by value, callers may hold it by pointer
+func proofValue(proof Proof) (Proof, error) {
+	switch p := proof.(type) {
+	case *bn254.Proof:
+		return result[Proof](deref(p))
+	case *bls12377.Proof:
+		return result[Proof](deref(p))
+	case *bls12381.Proof:
+		return result[Proof](deref(p))
+	case *bw6761.Proof:
+		return result[Proof](deref(p))
+	}
+	return proof, nil
+}
+
+// provingKeyValue returns the proving key of a backend by value, see proofValue
+func provingKeyValue(pk ProvingKey) (ProvingKey, error) {
+	switch p := pk.(type) {
+	case *bn254.ProvingKey:
+		return result[ProvingKey](deref(p))
+	case *bls12377.ProvingKey:
+		return result[ProvingKey](deref(p))
+	case *bls12381.ProvingKey:
+		return result[ProvingKey](deref(p))
+	case *bw6761.ProvingKey:
+		return result[ProvingKey](deref(p))
+	}
+	return pk, nil
+}
+
+// verifyingKeyValue returns the verifying key of a backend by value, see proofValue
+func verifyingKeyValue(vk VerifyingKey) (VerifyingKey, error) {
+	switch v := vk.(type) {
+	case *bn254.VerifyingKey:
+		return result[VerifyingKey](deref(v))
+	case *bls12377.VerifyingKey:
+		return result[VerifyingKey](deref(v))
+	case *bls12381.VerifyingKey:
+		return result[VerifyingKey](deref(v))
+	case *bw6761.VerifyingKey:
+		return result[VerifyingKey](deref(v))
+	}
+	return vk, nil
+}
+
+// deref returns the value p points to, a nil pointer being a missing value rather than a panic
+func deref[T any](p *T) (T, error) {
+	if p == nil {
+		var zero T
+		return zero, fmt.Errorf("missing key or proof")
+	}
+	return *p, nil
+}
+
+// sameCurve rejects a proof made on another curve than the verifying key
+func sameCurve(vk VerifyingKey, proof Proof) error {
+	if vk == nil || proof == nil {
+		return fmt.Errorf("missing verifying key or proof")
+	}
+	if vk.Curve() != proof.Curve() {
+		return fmt.Errorf("the proof was made on %s but the verifying key on %s", proof.Curve(), vk.Curve())
+	}
+	return nil
+}
+
+// keyPair returns the keys of a backend as interfaces, both nil on error
+func keyPair[P ProvingKey, V VerifyingKey](pk P, vk V, err error) (ProvingKey, VerifyingKey, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+// result returns the value of a backend as an interface, nil on error
+func result[I any, T any](v T, err error) (I, error) {
+	var zero I
+	if err != nil {
+		return zero, err
+	}
+	return any(v).(I), nil
+}
+
+func unsupportedCurve(curveID ecc.ID) error {
+	return fmt.Errorf("unsupported curve %s", curveID)
+}
+
+func unsupportedType(v interface{}) error {
+	return fmt.Errorf("unsupported type %T", v)
+}
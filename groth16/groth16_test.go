@@ -0,0 +1,140 @@
+package groth16_test
+
+import (
+	"math/big"
+	"r1cs-zk-go/groth16"
+	"r1cs-zk-go/groth16/bn254"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// the x^3 + 5x + 5 = 155 example of the README
+const (
+	exampleR1CS = `{
+		"nbPublicInputs": 2,
+		"L": [[0, 0, 0, 1], [0, 0, 0, 1]],
+		"R": [[0, 0, 0, 1], [0, 0, 1, 0]],
+		"O": [[0, 0, 1, 0], [-5, 1, 0, -5]]
+	}`
+	exampleWitness = `{"publicInputs": [1, 155], "privateInputs": [25, 5]}`
+)
+
+// TestSetupProveVerify runs the example of the README on every curve, with the keys and proofs of the
+// backends given by value and by pointer
+func TestSetupProveVerify(t *testing.T) {
+	r1csData, witnessData := exampleCircuit(t)
+	wrongOutput := []*big.Int{big.NewInt(1), big.NewInt(154)}
+
+	for _, curveID := range groth16.Curves {
+		t.Run(curveID.String(), func(t *testing.T) {
+			pk, vk, err := groth16.Setup(curveID, r1csData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, err := groth16.Prove(pk, r1csData, witnessData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proof.Curve() != curveID || pk.Curve() != curveID || vk.Curve() != curveID {
+				t.Fatalf("expected keys and a proof on %s, got %s, %s and %s", curveID, pk.Curve(), vk.Curve(), proof.Curve())
+			}
+
+			pkPtr, vkPtr := pointerTo(pk).(groth16.ProvingKey), pointerTo(vk).(groth16.VerifyingKey)
+			proofFromPtr, err := groth16.Prove(pkPtr, r1csData, witnessData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofPtr := pointerTo(proofFromPtr).(groth16.Proof)
+
+			for _, c := range []struct {
+				name  string
+				vk    groth16.VerifyingKey
+				proof groth16.Proof
+			}{
+				{"values", vk, proof},
+				{"pointers", vkPtr, proofPtr},
+				{"key by pointer", vkPtr, proof},
+			} {
+				if ok, err := groth16.Verify(c.vk, c.proof, witnessData.PublicInputs); !ok || err != nil {
+					t.Fatalf("%s: the proof doesn't verify: %v", c.name, err)
+				}
+				if ok, err := groth16.Verify(c.vk, c.proof, wrongOutput); ok || err != nil {
+					t.Fatalf("%s: expected the proof to fail for another output, got %t, %v", c.name, ok, err)
+				}
+			}
+
+			invalid, err := groth16.VerifyBatch(vkPtr, []groth16.BatchItem{
+				{Proof: proof, PublicInputs: witnessData.PublicInputs},
+				{Proof: proofPtr, PublicInputs: wrongOutput},
+				{Proof: proofFromPtr, PublicInputs: witnessData.PublicInputs},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(invalid, []int{1}) {
+				t.Fatalf("expected the second proof to be invalid, got %v", invalid)
+			}
+		})
+	}
+}
+
+// TestErrors checks that mismatched curves, unknown curves and missing values are errors rather than panics
+func TestErrors(t *testing.T) {
+	r1csData, witnessData := exampleCircuit(t)
+	_, bnVk, err := groth16.Setup(ecc.BN254, r1csData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blsPk, _, err := groth16.Setup(ecc.BLS12_381, r1csData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blsProof, err := groth16.Prove(blsPk, r1csData, witnessData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := groth16.Verify(bnVk, blsProof, witnessData.PublicInputs); err == nil {
+		t.Error("verified a BLS12-381 proof with a BN254 verifying key")
+	}
+	batch := []groth16.BatchItem{{Proof: blsProof, PublicInputs: witnessData.PublicInputs}}
+	if _, err := groth16.VerifyBatch(bnVk, batch); err == nil {
+		t.Error("batch verified a BLS12-381 proof with a BN254 verifying key")
+	}
+	if _, _, err := groth16.Setup(ecc.BLS24_315, r1csData); err == nil {
+		t.Error("ran the setup on an unsupported curve")
+	}
+	if _, err := groth16.Prove(nil, r1csData, witnessData); err == nil {
+		t.Error("proved without a proving key")
+	}
+	if _, err := groth16.Prove((*bn254.ProvingKey)(nil), r1csData, witnessData); err == nil {
+		t.Error("proved with a nil proving key")
+	}
+	if _, err := groth16.Verify(bnVk, (*bn254.Proof)(nil), witnessData.PublicInputs); err == nil {
+		t.Error("verified a nil proof")
+	}
+}
+
+func exampleCircuit(t *testing.T) (groth16.R1CS, groth16.Witness) {
+	r1csData, err := r1cs.ReadR1CS(strings.NewReader(exampleR1CS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessData, err := witness.ReadWitness(strings.NewReader(exampleWitness))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r1csData, witnessData
+}
+
+// pointerTo returns a pointer to a copy of v, e.g. a *bn254.ProvingKey for a bn254.ProvingKey
+func pointerTo(v any) any {
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	return p.Interface()
+}
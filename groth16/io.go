@@ -0,0 +1,185 @@
+package groth16
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"io"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-377"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/groth16/bn254"
+	"r1cs-zk-go/groth16/bw6-761"
+	"r1cs-zk-go/iden3"
+	"r1cs-zk-go/keys"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+)
+
+// Format selects how keys and proofs are written, readers detect it on their own
+type Format = keys.Format
+
+const (
+	FormatJSON             = keys.FormatJSON
+	FormatBinary           = keys.FormatBinary
+	FormatBinaryCompressed = keys.FormatBinaryCompressed
+)
+
+// ReadR1CS parses an R1CS in the dense or sparse JSON layout, or a circom .r1cs file.
+// A circom file must have been compiled for the scalar field of curveID, JSON values are reduced when proving.
+func ReadR1CS(r io.Reader, curveID ecc.ID) (R1CS, error) {
+	br := bufio.NewReader(r)
+	if hasMagic(br, "r1cs") {
+		return iden3.ReadR1CS(br, curveID)
+	}
+	return r1cs.ReadR1CS(br)
+}
+
+// ReadWitness parses a JSON witness with its public and private inputs, or a circom .wtns file
+// whose first nbPublicInputs values are public, see ReadR1CS for curveID
+func ReadWitness(r io.Reader, nbPublicInputs int, curveID ecc.ID) (Witness, error) {
+	br := bufio.NewReader(r)
+	if hasMagic(br, "wtns") {
+		return iden3.ReadWitness(br, nbPublicInputs, curveID)
+	}
+	return witness.ReadWitness(br)
+}
+
+func hasMagic(br *bufio.Reader, magic string) bool {
+	b, err := br.Peek(len(magic))
+	return err == nil && string(b) == magic
+}
+
+// ReadPublicInputs parses the public inputs written by WritePublicInputs
+func ReadPublicInputs(r io.Reader) ([]*big.Int, error) {
+	return witness.ReadPublicInputs(r)
+}
+
+// WritePublicInputs writes the public inputs the verifier needs next to a proof
+func WritePublicInputs(w io.Writer, publicInputs []*big.Int) error {
+	return witness.WritePublicInputs(w, publicInputs)
+}
+
+// ReadProvingKey parses a JSON or binary proving key, on the curve recorded in it, and checks that its points are valid
+func ReadProvingKey(r io.Reader) (ProvingKey, error) {
+	data, curveID, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curveID {
+	case ecc.BN254:
+		return result[ProvingKey](bn254.ReadProvingKey(data))
+	case ecc.BLS12_377:
+		return result[ProvingKey](bls12377.ReadProvingKey(data))
+	case ecc.BLS12_381:
+		return result[ProvingKey](bls12381.ReadProvingKey(data))
+	case ecc.BW6_761:
+		return result[ProvingKey](bw6761.ReadProvingKey(data))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// WriteProvingKey writes the proving key in the given format
+func WriteProvingKey(w io.Writer, pk ProvingKey, format Format) error {
+	switch pk := pk.(type) {
+	case bn254.ProvingKey:
+		return bn254.WriteProvingKey(w, pk, format)
+	case bls12377.ProvingKey:
+		return bls12377.WriteProvingKey(w, pk, format)
+	case bls12381.ProvingKey:
+		return bls12381.WriteProvingKey(w, pk, format)
+	case bw6761.ProvingKey:
+		return bw6761.WriteProvingKey(w, pk, format)
+	}
+	return unsupportedType(pk)
+}
+
+// ReadVerifyingKey parses a JSON or binary verifying key, see ReadProvingKey
+func ReadVerifyingKey(r io.Reader) (VerifyingKey, error) {
+	data, curveID, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curveID {
+	case ecc.BN254:
+		return result[VerifyingKey](bn254.ReadVerifyingKey(data))
+	case ecc.BLS12_377:
+		return result[VerifyingKey](bls12377.ReadVerifyingKey(data))
+	case ecc.BLS12_381:
+		return result[VerifyingKey](bls12381.ReadVerifyingKey(data))
+	case ecc.BW6_761:
+		return result[VerifyingKey](bw6761.ReadVerifyingKey(data))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// WriteVerifyingKey writes the verifying key in the given format
+func WriteVerifyingKey(w io.Writer, vk VerifyingKey, format Format) error {
+	switch vk := vk.(type) {
+	case bn254.VerifyingKey:
+		return bn254.WriteVerifyingKey(w, vk, format)
+	case bls12377.VerifyingKey:
+		return bls12377.WriteVerifyingKey(w, vk, format)
+	case bls12381.VerifyingKey:
+		return bls12381.WriteVerifyingKey(w, vk, format)
+	case bw6761.VerifyingKey:
+		return bw6761.WriteVerifyingKey(w, vk, format)
+	}
+	return unsupportedType(vk)
+}
+
+// ReadProof parses a JSON or binary proof, see ReadProvingKey. A proof with invalid points is returned as a *keys.PointError.
+func ReadProof(r io.Reader) (Proof, error) {
+	data, curveID, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curveID {
+	case ecc.BN254:
+		return result[Proof](bn254.ReadProof(data))
+	case ecc.BLS12_377:
+		return result[Proof](bls12377.ReadProof(data))
+	case ecc.BLS12_381:
+		return result[Proof](bls12381.ReadProof(data))
+	case ecc.BW6_761:
+		return result[Proof](bw6761.ReadProof(data))
+	}
+	return nil, unsupportedCurve(curveID)
+}
+
+// WriteProof writes the proof in the given format
+func WriteProof(w io.Writer, proof Proof, format Format) error {
+	switch proof := proof.(type) {
+	case bn254.Proof:
+		return bn254.WriteProof(w, proof, format)
+	case bls12377.Proof:
+		return bls12377.WriteProof(w, proof, format)
+	case bls12381.Proof:
+		return bls12381.WriteProof(w, proof, format)
+	case bw6761.Proof:
+		return bw6761.WriteProof(w, proof, format)
+	}
+	return unsupportedType(proof)
+}
+
+// readAll reads a key or proof and the curve recorded in it, so that it is decoded by the backend of that curve
+func readAll(r io.Reader) (*bytes.Reader, ecc.ID, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, ecc.UNKNOWN, fmt.Errorf("failed to read: %v", err)
+	}
+	curveID, err := keys.DetectCurve(data)
+	if err != nil {
+		return nil, ecc.UNKNOWN, err
+	}
+	return bytes.NewReader(data), curveID, nil
+}
+
+// ParseFormat returns the format named "json", "binary" or "compressed"
+func ParseFormat(name string) (Format, error) {
+	return keys.ParseFormat(name)
+}
@@ -0,0 +1,182 @@
+// Package iden3 reads and writes the binary .r1cs and .wtns files of circom and snarkjs.
+//
+// Both are iden3 "binfiles": a 4 bytes magic, a uint32 version and a uint32 number of sections, every section
+// being a uint32 type, a uint64 size and its content. Integers are little endian, and field elements are
+// written on n8 bytes in little endian and in canonical (not Montgomery) form. The prime of the field, recorded in
+// the header, tells which curve the file was made for.
+package iden3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/keys"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// binFile holds the sections of a binfile by type
+type binFile struct {
+	version  uint32
+	sections map[uint32][]byte
+}
+
+func parseBinFile(data []byte, magic string) (binFile, error) {
+	r := &binReader{data: data}
+	if !bytes.Equal(r.bytes(4), []byte(magic)) {
+		return binFile{}, fmt.Errorf("not a .%s file, bad magic", magic)
+	}
+	f := binFile{version: r.u32(), sections: map[uint32][]byte{}}
+	nbSections := r.u32()
+	for i := uint32(0); i < nbSections && r.err == nil; i++ {
+		sectionType := r.u32()
+		size := r.u64()
+		if r.err == nil && size > uint64(len(r.data)) {
+			return binFile{}, fmt.Errorf("section %d of type %d is truncated", i, sectionType)
+		}
+		content := r.bytes(int(size))
+		if _, ok := f.sections[sectionType]; ok {
+			return binFile{}, fmt.Errorf("duplicate section of type %d", sectionType)
+		}
+		f.sections[sectionType] = content
+	}
+	if r.err != nil {
+		return binFile{}, r.err
+	}
+
+	return f, nil
+}
+
+func (f binFile) section(sectionType uint32, name string) (*binReader, error) {
+	content, ok := f.sections[sectionType]
+	if !ok {
+		return nil, fmt.Errorf("missing %s section", name)
+	}
+	return &binReader{data: content, name: name}, nil
+}
+
+// binReader reads little endian values and keeps the first error, reads past the end return zeros
+type binReader struct {
+	data []byte
+	name string
+	err  error
+}
+
+func (r *binReader) bytes(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = fmt.Errorf("unexpected end of %s", r.sectionName())
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *binReader) sectionName() string {
+	if r.name == "" {
+		return "file"
+	}
+	return r.name + " section"
+}
+
+func (r *binReader) u32() uint32 {
+	return binary.LittleEndian.Uint32(r.bytes(4))
+}
+
+func (r *binReader) u64() uint64 {
+	return binary.LittleEndian.Uint64(r.bytes(8))
+}
+
+// field is the prime field of a binfile, the scalar field of one of the curves
+type field struct {
+	curve   ecc.ID
+	n8      int
+	modulus *big.Int
+}
+
+// curveField returns the scalar field of the curve, its elements are written on a whole number of 64 bits words
+func curveField(curveID ecc.ID) field {
+	modulus := curveID.ScalarField()
+	return field{curve: curveID, n8: (modulus.BitLen() + 63) / 64 * 8, modulus: modulus}
+}
+
+// supportedField returns the scalar field of curveID, which must be one of the curves
+func supportedField(curveID ecc.ID) (field, error) {
+	for _, c := range keys.Curves {
+		if c == curveID {
+			return curveField(c), nil
+		}
+	}
+	return field{}, fmt.Errorf("unsupported curve %s", curveID)
+}
+
+// check rejects a file made for another field than the scalar field of curveID
+func (f field) check(curveID ecc.ID) error {
+	if f.curve != curveID {
+		return fmt.Errorf("the prime field is %s's scalar field, expected %s's", f.curve, curveID)
+	}
+	return nil
+}
+
+// element reads a field element on n8 bytes, it must be reduced
+func (r *binReader) element(f field) *big.Int {
+	b := r.bytes(f.n8)
+	if r.err != nil {
+		return new(big.Int)
+	}
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b) - 1 - i] = b[i]
+	}
+	e := new(big.Int).SetBytes(be)
+	if e.Cmp(f.modulus) >= 0 {
+		r.err = fmt.Errorf("%s: field element is not reduced modulo r", r.sectionName())
+	}
+	return e
+}
+
+// prime reads the field description that starts both headers, it must be the scalar field of one of the curves
+func (r *binReader) prime() field {
+	n8 := r.u32()
+	if r.err != nil {
+		return field{}
+	}
+	if n8 == 0 || n8 > 1024 {
+		r.err = fmt.Errorf("field elements can't be %d bytes long", n8)
+		return field{}
+	}
+
+	prime := r.bytes(int(n8))
+	if r.err != nil {
+		return field{}
+	}
+	for _, curveID := range keys.Curves {
+		f := curveField(curveID)
+		if f.n8 == int(n8) && bytes.Equal(prime, littleEndian(f.modulus, f.n8)) {
+			return f
+		}
+	}
+
+	r.err = fmt.Errorf("the prime field is not the scalar field of a supported curve, compile the circuit with --prime bn128 or bls12381")
+	return field{}
+}
+
+// littleEndian writes v on n bytes in little endian
+func littleEndian(v *big.Int, n int) []byte {
+	b := v.FillBytes(make([]byte, n))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// end reports bytes left in the section, which would mean the counts don't match the content
+func (r *binReader) end() error {
+	if r.err == nil && len(r.data) != 0 {
+		return fmt.Errorf("%d unexpected bytes at the end of %s", len(r.data), r.sectionName())
+	}
+	return r.err
+}
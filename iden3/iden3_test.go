@@ -0,0 +1,165 @@
+package iden3_test
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"r1cs-zk-go/iden3"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// The golden files hold the x^3 + 5x + 5 = 155 example of the README. They were written by an encoder of the
+// iden3 binfile format independent from this package: circom wires [1, out, x^2, x] with out as the public
+// input, coefficients and values reduced modulo the prime and every wire its own label.
+const (
+	exampleR1CS = `{
+		"nbPublicInputs": 2,
+		"L": [[0, 0, 0, 1], [0, 0, 0, 1]],
+		"R": [[0, 0, 0, 1], [0, 0, 1, 0]],
+		"O": [[0, 0, 1, 0], [-5, 1, 0, -5]]
+	}`
+	exampleWitness = `{"publicInputs": [1, 155], "privateInputs": [25, 5]}`
+)
+
+var goldenCurves = map[string]ecc.ID{"bn254": ecc.BN254, "bls12-381": ecc.BLS12_381}
+
+func TestWriteR1CSGolden(t *testing.T) {
+	r1csData := exampleR1CSData(t)
+	for name, curveID := range goldenCurves {
+		golden := readGolden(t, "testdata/example_" + name + ".r1cs")
+
+		var buf bytes.Buffer
+		if err := iden3.WriteR1CS(&buf, r1csData, curveID); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), golden) {
+			t.Errorf("%s: the written .r1cs differs from the golden file\ngot  %x\nwant %x", name, buf.Bytes(), golden)
+		}
+	}
+}
+
+func TestReadR1CSGolden(t *testing.T) {
+	r1csData := exampleR1CSData(t)
+	for name, curveID := range goldenCurves {
+		f, err := iden3.ReadR1CSFile(bytes.NewReader(readGolden(t, "testdata/example_" + name + ".r1cs")))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if f.Curve != curveID || f.NbPubOut != 0 || f.NbPubIn != 1 || f.NbPrvIn != 2 || f.NbLabels != 4 {
+			t.Errorf("%s: unexpected header %s, %d public outputs, %d public inputs, %d private inputs, %d labels", name, f.Curve, f.NbPubOut, f.NbPubIn, f.NbPrvIn, f.NbLabels)
+		}
+		for i, label := range f.WireToLabel {
+			if label != uint64(i) {
+				t.Errorf("%s: wire %d has label %d", name, i, label)
+			}
+		}
+		if f.R1CS.NbVariables != r1csData.NbVariables || f.R1CS.NbPublicInputs != r1csData.NbPublicInputs {
+			t.Fatalf("%s: expected %d wires and %d public inputs, got %d and %d", name, r1csData.NbVariables, r1csData.NbPublicInputs, f.R1CS.NbVariables, f.R1CS.NbPublicInputs)
+		}
+		if len(f.R1CS.Constraints) != len(r1csData.Constraints) {
+			t.Fatalf("%s: expected %d constraints, got %d", name, len(r1csData.Constraints), len(f.R1CS.Constraints))
+		}
+
+		r := curveID.ScalarField()
+		for i, c := range r1csData.Constraints {
+			read := f.R1CS.Constraints[i]
+			for j, lc := range [][2]r1cs.LinearCombination{{c.L, read.L}, {c.R, read.R}, {c.O, read.O}} {
+				if !sameLinearCombination(lc[0], lc[1], r) {
+					t.Errorf("%s: constraint %d, matrix %d: expected %v, got %v", name, i, j, lc[0], lc[1])
+				}
+			}
+		}
+	}
+}
+
+func TestWriteWitnessGolden(t *testing.T) {
+	witnessData := exampleWitnessData(t)
+	for name, curveID := range goldenCurves {
+		golden := readGolden(t, "testdata/example_" + name + ".wtns")
+
+		var buf bytes.Buffer
+		if err := iden3.WriteWitness(&buf, witnessData, curveID); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), golden) {
+			t.Errorf("%s: the written .wtns differs from the golden file\ngot  %x\nwant %x", name, buf.Bytes(), golden)
+		}
+	}
+}
+
+func TestReadWitnessGolden(t *testing.T) {
+	witnessData := exampleWitnessData(t)
+	for name, curveID := range goldenCurves {
+		read, err := iden3.ReadWitness(bytes.NewReader(readGolden(t, "testdata/example_" + name + ".wtns")), len(witnessData.PublicInputs), curveID)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !sameValues(read.PublicInputs, witnessData.PublicInputs) || !sameValues(read.PrivateInputs, witnessData.PrivateInputs) {
+			t.Errorf("%s: expected %v %v, got %v %v", name, witnessData.PublicInputs, witnessData.PrivateInputs, read.PublicInputs, read.PrivateInputs)
+		}
+
+		// a file over another field is rejected
+		other := ecc.BLS12_381
+		if curveID == other {
+			other = ecc.BN254
+		}
+		if _, err := iden3.ReadWitness(bytes.NewReader(readGolden(t, "testdata/example_" + name + ".wtns")), 2, other); err == nil {
+			t.Errorf("%s: the .wtns was read over %s's field", name, other)
+		}
+	}
+}
+
+func exampleR1CSData(t *testing.T) r1cs.R1CSData {
+	r1csData, err := r1cs.ReadR1CS(strings.NewReader(exampleR1CS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r1csData
+}
+
+func exampleWitnessData(t *testing.T) witness.WitnessData {
+	witnessData, err := witness.ReadWitness(strings.NewReader(exampleWitness))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return witnessData
+}
+
+func readGolden(t *testing.T, path string) []byte {
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return golden
+}
+
+// sameLinearCombination compares the coefficients modulo r, the file holds -5 as r - 5
+func sameLinearCombination(expected, got r1cs.LinearCombination, r *big.Int) bool {
+	if len(expected) != len(got) {
+		return false
+	}
+	for i := range expected {
+		diff := new(big.Int).Sub(expected[i].Coeff, got[i].Coeff)
+		if expected[i].Wire != got[i].Wire || diff.Mod(diff, r).Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sameValues(expected, got []*big.Int) bool {
+	if len(expected) != len(got) {
+		return false
+	}
+	for i := range expected {
+		if expected[i].Cmp(got[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package iden3
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"math/big"
+	"r1cs-zk-go/r1cs"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+const (
+	r1csMagic   = "r1cs"
+	r1csVersion = 1
+
+	sectionHeader      = 1
+	sectionConstraints = 2
+	sectionWire2Label  = 3
+	// custom gates are only used by PLONK circuits
+	sectionCustomGatesList = 4
+	sectionCustomGatesUses = 5
+)
+
+// R1CSFile is the content of a circom .r1cs file. Wires are ordered as
+// [1, public outputs..., public inputs..., private inputs..., internal signals...],
+// so the first 1 + NbPubOut + NbPubIn wires are the public inputs of the proof.
+type R1CSFile struct {
+	R1CS     r1cs.R1CSData
+	// Curve is the curve whose scalar field is the prime of the file
+	Curve    ecc.ID
+	NbPubOut int
+	NbPubIn  int
+	NbPrvIn  int
+	NbLabels uint64
+	// WireToLabel maps every wire to the id of the circom signal it carries
+	WireToLabel []uint64
+}
+
+// ReadR1CS reads a circom .r1cs file, the public inputs being the constant one and the public outputs and inputs.
+// It is rejected when its prime isn't the scalar field of curveID.
+func ReadR1CS(r io.Reader, curveID ecc.ID) (r1cs.R1CSData, error) {
+	f, err := ReadR1CSFile(r)
+	if err != nil {
+		return r1cs.R1CSData{}, err
+	}
+	if err := curveField(f.Curve).check(curveID); err != nil {
+		return r1cs.R1CSData{}, err
+	}
+	return f.R1CS, nil
+}
+
+// ReadR1CSFile reads a circom .r1cs file, it is rejected when its prime isn't the scalar field of one of the curves
+func ReadR1CSFile(r io.Reader) (R1CSFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return R1CSFile{}, fmt.Errorf("failed to read .r1cs file: %v", err)
+	}
+	bf, err := parseBinFile(data, r1csMagic)
+	if err != nil {
+		return R1CSFile{}, err
+	}
+	if bf.version != r1csVersion {
+		return R1CSFile{}, fmt.Errorf("unsupported .r1cs version %d", bf.version)
+	}
+	if _, ok := bf.sections[sectionCustomGatesList]; ok {
+		return R1CSFile{}, fmt.Errorf("custom gates are not supported by Groth16")
+	}
+	if _, ok := bf.sections[sectionCustomGatesUses]; ok {
+		return R1CSFile{}, fmt.Errorf("custom gates are not supported by Groth16")
+	}
+
+	header, err := bf.section(sectionHeader, "header")
+	if err != nil {
+		return R1CSFile{}, err
+	}
+	fr := header.prime()
+	nbWires := header.u32()
+	f := R1CSFile{
+		Curve:    fr.curve,
+		NbPubOut: int(header.u32()),
+		NbPubIn:  int(header.u32()),
+		NbPrvIn:  int(header.u32()),
+		NbLabels: header.u64(),
+	}
+	nbConstraints := header.u32()
+	if err := header.end(); err != nil {
+		return R1CSFile{}, err
+	}
+
+	nbPublicInputs := 1 + f.NbPubOut + f.NbPubIn
+	if uint64(nbPublicInputs + f.NbPrvIn) > uint64(nbWires) {
+		return R1CSFile{}, fmt.Errorf("%d public and %d private inputs don't fit in %d wires", nbPublicInputs, f.NbPrvIn, nbWires)
+	}
+
+	section, err := bf.section(sectionConstraints, "constraints")
+	if err != nil {
+		return R1CSFile{}, err
+	}
+	// every constraint takes at least 12 bytes, this bounds the allocation
+	if uint64(nbConstraints) * 12 > uint64(len(section.data)) {
+		return R1CSFile{}, fmt.Errorf("the constraints section is too short for %d constraints", nbConstraints)
+	}
+	constraints := make([]r1cs.Constraint, nbConstraints)
+	for i := range constraints {
+		constraints[i] = r1cs.Constraint{
+			L: readLinearCombination(section, fr, nbWires),
+			R: readLinearCombination(section, fr, nbWires),
+			O: readLinearCombination(section, fr, nbWires),
+		}
+	}
+	if err := section.end(); err != nil {
+		return R1CSFile{}, err
+	}
+
+	f.R1CS = r1cs.R1CSData{
+		Constraints:    constraints,
+		NbVariables:    int(nbWires),
+		NbPublicInputs: nbPublicInputs,
+	}
+	if err := f.R1CS.Validate(); err != nil {
+		return R1CSFile{}, err
+	}
+
+	labels, err := bf.section(sectionWire2Label, "wire to label")
+	if err != nil {
+		return R1CSFile{}, err
+	}
+	if uint64(len(labels.data)) != uint64(nbWires) * 8 {
+		return R1CSFile{}, fmt.Errorf("the wire to label section must map %d wires", nbWires)
+	}
+	f.WireToLabel = make([]uint64, nbWires)
+	for i := range f.WireToLabel {
+		f.WireToLabel[i] = labels.u64()
+	}
+
+	return f, nil
+}
+
+// readLinearCombination reads the terms of a linear combination, sorting them by wire and merging duplicates
+func readLinearCombination(r *binReader, fr field, nbWires uint32) r1cs.LinearCombination {
+	nbTerms := r.u32()
+	if r.err == nil && uint64(nbTerms) * uint64(4 + fr.n8) > uint64(len(r.data)) {
+		r.err = fmt.Errorf("unexpected end of %s", r.sectionName())
+	}
+	if r.err != nil {
+		return nil
+	}
+
+	lc := make(r1cs.LinearCombination, 0, nbTerms)
+	for i := uint32(0); i < nbTerms; i++ {
+		wire := r.u32()
+		coeff := r.element(fr)
+		if r.err == nil && wire >= nbWires {
+			r.err = fmt.Errorf("constraint references wire %d but there are %d wires", wire, nbWires)
+		}
+		lc = append(lc, r1cs.Term{Wire: int(wire), Coeff: coeff})
+	}
+	if r.err != nil {
+		return nil
+	}
+
+	sort.SliceStable(lc, func(i, j int) bool { return lc[i].Wire < lc[j].Wire })
+	merged := lc[:0]
+	for _, t := range lc {
+		if n := len(merged); n > 0 && merged[n-1].Wire == t.Wire {
+			sum := new(big.Int).Add(merged[n-1].Coeff, t.Coeff)
+			merged[n-1].Coeff = sum.Mod(sum, fr.modulus)
+			continue
+		}
+		merged = append(merged, t)
+	}
+	res := make(r1cs.LinearCombination, 0, len(merged))
+	for _, t := range merged {
+		if t.Coeff.Sign() != 0 {
+			res = append(res, t)
+		}
+	}
+
+	return res
+}
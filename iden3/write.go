@@ -0,0 +1,163 @@
+package iden3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/witness"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// NewR1CSFile describes an R1CS with circom's wire layout. Wire 0 must be the constant one and the other
+// public inputs are written as public inputs, the private wires as private inputs. Every wire is its own label.
+// The coefficients are written in the scalar field of curveID.
+func NewR1CSFile(r1csData r1cs.R1CSData, curveID ecc.ID) (R1CSFile, error) {
+	if r1csData.NbPublicInputs < 1 {
+		return R1CSFile{}, fmt.Errorf("circom expects the constant one as first public input, the R1CS has no public input")
+	}
+
+	wireToLabel := make([]uint64, r1csData.NbVariables)
+	for i := range wireToLabel {
+		wireToLabel[i] = uint64(i)
+	}
+
+	return R1CSFile{
+		R1CS:        r1csData,
+		Curve:       curveID,
+		NbPubOut:    0,
+		NbPubIn:     r1csData.NbPublicInputs - 1,
+		NbPrvIn:     r1csData.NbVariables - r1csData.NbPublicInputs,
+		NbLabels:    uint64(r1csData.NbVariables),
+		WireToLabel: wireToLabel,
+	}, nil
+}
+
+// WriteR1CS writes the R1CS as a circom .r1cs file, see NewR1CSFile
+func WriteR1CS(w io.Writer, r1csData r1cs.R1CSData, curveID ecc.ID) error {
+	f, err := NewR1CSFile(r1csData, curveID)
+	if err != nil {
+		return err
+	}
+	return WriteR1CSFile(w, f)
+}
+
+// WriteR1CSFile writes a circom .r1cs file with the header, constraints and wire to label sections
+func WriteR1CSFile(w io.Writer, f R1CSFile) error {
+	if 1 + f.NbPubOut + f.NbPubIn != f.R1CS.NbPublicInputs {
+		return fmt.Errorf("1 + %d public outputs + %d public inputs don't match the %d public inputs of the R1CS", f.NbPubOut, f.NbPubIn, f.R1CS.NbPublicInputs)
+	}
+	if len(f.WireToLabel) != f.R1CS.NbVariables {
+		return fmt.Errorf("the wire to label map has %d wires, the R1CS has %d", len(f.WireToLabel), f.R1CS.NbVariables)
+	}
+	if err := f.R1CS.Validate(); err != nil {
+		return err
+	}
+
+	fr, err := supportedField(f.Curve)
+	if err != nil {
+		return err
+	}
+	var header binWriter
+	header.prime(fr)
+	header.u32(uint32(f.R1CS.NbVariables))
+	header.u32(uint32(f.NbPubOut))
+	header.u32(uint32(f.NbPubIn))
+	header.u32(uint32(f.NbPrvIn))
+	header.u64(f.NbLabels)
+	header.u32(uint32(f.R1CS.NbConstraints()))
+
+	var constraints binWriter
+	for _, c := range f.R1CS.Constraints {
+		for _, lc := range []r1cs.LinearCombination{c.L, c.R, c.O} {
+			constraints.u32(uint32(len(lc)))
+			for _, t := range lc {
+				constraints.u32(uint32(t.Wire))
+				constraints.element(fr, t.Coeff)
+			}
+		}
+	}
+
+	var labels binWriter
+	for _, label := range f.WireToLabel {
+		labels.u64(label)
+	}
+
+	return writeBinFile(w, r1csMagic, r1csVersion, map[uint32]*binWriter{
+		sectionHeader:      &header,
+		sectionConstraints: &constraints,
+		sectionWire2Label:  &labels,
+	})
+}
+
+// WriteWitness writes the witness as a .wtns file, public inputs first, with its values in the scalar field of curveID
+func WriteWitness(w io.Writer, witnessData witness.WitnessData, curveID ecc.ID) error {
+	fr, err := supportedField(curveID)
+	if err != nil {
+		return err
+	}
+	values := witnessData.Values()
+	if len(values) == 0 || new(big.Int).Mod(values[0], fr.modulus).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("circom expects the constant one as first witness value")
+	}
+
+	var header binWriter
+	header.prime(fr)
+	header.u32(uint32(len(values)))
+
+	var content binWriter
+	for _, v := range values {
+		content.element(fr, v)
+	}
+
+	return writeBinFile(w, wtnsMagic, wtnsVersion, map[uint32]*binWriter{
+		sectionWtnsHeader: &header,
+		sectionWtnsValues: &content,
+	})
+}
+
+// writeBinFile writes the sections in increasing type order
+func writeBinFile(w io.Writer, magic string, version uint32, sections map[uint32]*binWriter) error {
+	var file binWriter
+	file.buf.WriteString(magic)
+	file.u32(version)
+	file.u32(uint32(len(sections)))
+	for sectionType := uint32(1); int(sectionType) <= len(sections); sectionType++ {
+		section := sections[sectionType]
+		file.u32(sectionType)
+		file.u64(uint64(section.buf.Len()))
+		file.buf.Write(section.buf.Bytes())
+	}
+
+	_, err := w.Write(file.buf.Bytes())
+	return err
+}
+
+type binWriter struct {
+	buf bytes.Buffer
+}
+
+func (bw *binWriter) u32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	bw.buf.Write(b[:])
+}
+
+func (bw *binWriter) u64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	bw.buf.Write(b[:])
+}
+
+// element writes v reduced modulo the prime on n8 bytes
+func (bw *binWriter) element(f field, v *big.Int) {
+	bw.buf.Write(littleEndian(new(big.Int).Mod(v, f.modulus), f.n8))
+}
+
+// prime writes the field description that starts both headers
+func (bw *binWriter) prime(f field) {
+	bw.u32(uint32(f.n8))
+	bw.buf.Write(littleEndian(f.modulus, f.n8))
+}
@@ -0,0 +1,91 @@
+package iden3
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"r1cs-zk-go/witness"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+const (
+	wtnsMagic   = "wtns"
+	wtnsVersion = 2
+
+	sectionWtnsHeader = 1
+	sectionWtnsValues = 2
+)
+
+// WitnessFile is the content of a .wtns file
+type WitnessFile struct {
+	// Curve is the curve whose scalar field is the prime of the file
+	Curve  ecc.ID
+	Values []*big.Int
+}
+
+// ReadWitness reads a .wtns file, which holds one value per wire. The first nbPublicInputs values,
+// the constant one included, are the public inputs, see R1CSFile. It is rejected when its prime
+// isn't the scalar field of curveID.
+func ReadWitness(r io.Reader, nbPublicInputs int, curveID ecc.ID) (witness.WitnessData, error) {
+	f, err := ReadWitnessFile(r)
+	if err != nil {
+		return witness.WitnessData{}, err
+	}
+	if err := curveField(f.Curve).check(curveID); err != nil {
+		return witness.WitnessData{}, err
+	}
+	values := f.Values
+	if nbPublicInputs < 1 || nbPublicInputs > len(values) {
+		return witness.WitnessData{}, fmt.Errorf("expected at least %d witness values, got %d", nbPublicInputs, len(values))
+	}
+	if values[0].Cmp(big.NewInt(1)) != 0 {
+		return witness.WitnessData{}, fmt.Errorf("the first witness value must be the constant one")
+	}
+
+	return witness.WitnessData{
+		PublicInputs:  values[:nbPublicInputs],
+		PrivateInputs: values[nbPublicInputs:],
+	}, nil
+}
+
+// ReadWitnessFile reads a .wtns file, it is rejected when its prime isn't the scalar field of one of the curves
+func ReadWitnessFile(r io.Reader) (WitnessFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return WitnessFile{}, fmt.Errorf("failed to read .wtns file: %v", err)
+	}
+	bf, err := parseBinFile(data, wtnsMagic)
+	if err != nil {
+		return WitnessFile{}, err
+	}
+	if bf.version != wtnsVersion {
+		return WitnessFile{}, fmt.Errorf("unsupported .wtns version %d", bf.version)
+	}
+
+	header, err := bf.section(sectionWtnsHeader, "header")
+	if err != nil {
+		return WitnessFile{}, err
+	}
+	fr := header.prime()
+	nbValues := header.u32()
+	if err := header.end(); err != nil {
+		return WitnessFile{}, err
+	}
+
+	section, err := bf.section(sectionWtnsValues, "values")
+	if err != nil {
+		return WitnessFile{}, err
+	}
+	if uint64(len(section.data)) != uint64(nbValues) * uint64(fr.n8) {
+		return WitnessFile{}, fmt.Errorf("the values section must hold %d values", nbValues)
+	}
+	values := make([]*big.Int, nbValues)
+	for i := range values {
+		values[i] = section.element(fr)
+	}
+	if err := section.end(); err != nil {
+		return WitnessFile{}, err
+	}
+
+	return WitnessFile{Curve: fr.curve, Values: values}, nil
+}
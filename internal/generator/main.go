@@ -0,0 +1,71 @@
+// Command generator writes the Groth16 backend of every supported curve, groth16/<curve>, from the templates.
+// gnark-crypto exposes the same API for every curve, so a backend is the same code with another import path.
+// Run it with go generate ./groth16 after editing a template.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.go.tmpl
+var templates embed.FS
+
+type curve struct {
+	// Name is the curve as written in comments
+	Name string
+	// Package is the name of the backend, Dir its directory and gnark-crypto's one
+	Package, Dir string
+	// ID is the name of the ecc.ID constant
+	ID string
+	// G2Fp is set when G2 is defined over Fp rather than Fp2, the coordinates of G2 points are then single elements
+	G2Fp bool
+}
+
+var curves = []curve{
+	{Name: "BN254", Package: "bn254", Dir: "bn254", ID: "BN254"},
+	{Name: "BLS12-377", Package: "bls12377", Dir: "bls12-377", ID: "BLS12_377"},
+	{Name: "BLS12-381", Package: "bls12381", Dir: "bls12-381", ID: "BLS12_381"},
+	{Name: "BW6-761", Package: "bw6761", Dir: "bw6-761", ID: "BW6_761", G2Fp: true},
+}
+
+func main() {
+	tmpl, err := template.ParseFS(templates, "templates/*.go.tmpl")
+	if err != nil {
+		fail("failed to parse the templates: %v", err)
+	}
+
+	for _, c := range curves {
+		if err := os.MkdirAll(c.Dir, 0755); err != nil {
+			fail("failed to create %s: %v", c.Dir, err)
+		}
+		for _, t := range tmpl.Templates() {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "// Code generated by internal/generator from %s, DO NOT EDIT.\n\n", t.Name())
+			if err := t.Execute(&buf, c); err != nil {
+				fail("failed to execute %s for %s: %v", t.Name(), c.Name, err)
+			}
+
+			path := filepath.Join(c.Dir, strings.TrimSuffix(t.Name(), ".tmpl"))
+			// gofmt the output, which also catches a template rendering invalid Go
+			src, err := format.Source(buf.Bytes())
+			if err != nil {
+				fail("failed to format %s: %v", path, err)
+			}
+			if err := os.WriteFile(path, src, 0644); err != nil {
+				fail("failed to write %s: %v", path, err)
+			}
+		}
+	}
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format + "\n", args...)
+	os.Exit(1)
+}
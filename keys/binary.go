@@ -0,0 +1,142 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Binary layout of keys, proofs and ceremony files, all integers are big endian:
+//
+//   magic "R1ZK" | version (1 byte) | kind (1 byte) | flags (1 byte) | curve (1 byte) | sections...
+//
+// curve is the ecc.ID of the curve the key or proof was made on. Version 1 files have no curve byte,
+// they were all made on BLS12-381. The proving keys of versions 1 and 2, and the phase 2 files of version 2,
+// hold SRS1 and SRS2 instead of the per-wire points and are rejected with ErrOutdatedProvingKey.
+// Every section is a uint32 count followed by that many points, in the order of the struct fields.
+// Points use gnark-crypto's encoding, compressed (Bytes) when the compressed flag is set and uncompressed
+// (RawBytes) otherwise. The optional e(alpha, beta) of the verifying key is a section of 0 or 1 GT element.
+// The beacon of a ceremony contribution is a section of bytes and its number of iterations a bare count.
+// A phase 2 holds the sections of a proving key then of a verifying key, followed by its own.
+// The sections are written and read by the backend of the curve.
+var binaryMagic = []byte("R1ZK")
+
+const binaryVersion = 3
+
+// perWireVersion is the first version whose proving keys hold the per-wire points
+const perWireVersion = 3
+
+// ErrOutdatedProvingKey is returned for a proving key, or a phase 2, written before the per-wire points
+// replaced SRS1 and SRS2 in proving keys
+var ErrOutdatedProvingKey = errors.New("the proving key was made by an older version without the per-wire points, run the setup again")
+
+// Kind is what a binary file holds
+type Kind byte
+
+const (
+	KindProvingKey Kind = iota + 1
+	KindVerifyingKey
+	KindProof
+	KindPhase1
+	KindPhase2
+)
+
+const flagCompressed = 1
+
+// MaxSectionLen bounds the allocation made for a section before its points are read
+const MaxSectionLen = 1 << 28
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindProvingKey:
+		return "proving key"
+	case KindVerifyingKey:
+		return "verifying key"
+	case KindProof:
+		return "proof"
+	case KindPhase1:
+		return "phase 1"
+	case KindPhase2:
+		return "phase 2"
+	}
+	return fmt.Sprintf("unknown kind %d", kind)
+}
+
+// WriteBinaryHeader starts a binary key or proof made on curveID
+func WriteBinaryHeader(buf *bytes.Buffer, kind Kind, curveID ecc.ID, compressed bool) {
+	var flags byte
+	if compressed {
+		flags |= flagCompressed
+	}
+	buf.Write(binaryMagic)
+	buf.Write([]byte{binaryVersion, byte(kind), flags, byte(curveID)})
+}
+
+// ReadBinaryHeader reads the header of a binary file, checks that it holds a kind made on curveID
+// and returns whether its points are compressed
+func ReadBinaryHeader(r io.Reader, kind Kind, curveID ecc.ID) (bool, error) {
+	header := make([]byte, 7, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return false, fmt.Errorf("failed to read binary header: %v", err)
+	}
+	if header[4] != 1 {
+		header = header[:8]
+		if _, err := io.ReadFull(r, header[7:]); err != nil {
+			return false, fmt.Errorf("failed to read binary header: %v", err)
+		}
+	}
+
+	recorded, err := parseBinaryHeader(header)
+	if err != nil {
+		return false, err
+	}
+	if Kind(header[5]) != kind {
+		return false, fmt.Errorf("expected a binary %s, got a %s", kind, Kind(header[5]))
+	}
+	if (kind == KindProvingKey || kind == KindPhase2) && header[4] < perWireVersion {
+		return false, ErrOutdatedProvingKey
+	}
+	if err := checkCurve(recorded, curveID); err != nil {
+		return false, err
+	}
+
+	return header[6] & flagCompressed != 0, nil
+}
+
+// binaryCurve returns the curve recorded in the header of a binary file
+func binaryCurve(data []byte) (ecc.ID, error) {
+	size := 8
+	if len(data) > 4 && data[4] == 1 {
+		size = 7
+	}
+	if len(data) < size {
+		return ecc.UNKNOWN, fmt.Errorf("failed to read binary header: %v", io.ErrUnexpectedEOF)
+	}
+	return parseBinaryHeader(data[:size])
+}
+
+// parseBinaryHeader checks the magic, version and flags of a header and returns its curve
+func parseBinaryHeader(header []byte) (ecc.ID, error) {
+	if !bytes.Equal(header[:4], binaryMagic) {
+		return ecc.UNKNOWN, fmt.Errorf("not a binary key or proof, bad magic %q", header[:4])
+	}
+	if header[4] < 1 || header[4] > binaryVersion {
+		return ecc.UNKNOWN, fmt.Errorf("unsupported binary format version %d", header[4])
+	}
+	if header[6] & ^byte(flagCompressed) != 0 {
+		return ecc.UNKNOWN, fmt.Errorf("unknown binary format flags %#x", header[6])
+	}
+	if header[4] == 1 {
+		return DefaultCurve, nil
+	}
+
+	curveID := ecc.ID(header[7])
+	for _, c := range Curves {
+		if c == curveID {
+			return curveID, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unsupported curve id %d", header[7])
+}
@@ -0,0 +1,32 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMalformedCoordinate is returned when a coordinate isn't a decimal integer in [0, p)
+	ErrMalformedCoordinate = errors.New("malformed coordinate")
+	// ErrNotOnCurve is returned when the coordinates don't satisfy the curve equation
+	ErrNotOnCurve = errors.New("point is not on the curve")
+	// ErrNotInSubgroup is returned when the point is on the curve but outside the prime order subgroup
+	ErrNotInSubgroup = errors.New("point is not in the prime order subgroup")
+	// ErrIdentityPoint is returned when the point at infinity is found where it is not expected
+	ErrIdentityPoint = errors.New("unexpected point at infinity")
+)
+
+// PointError reports which field of a key or proof failed to decode. Err wraps one of the errors above,
+// so callers can check the reason with errors.Is.
+type PointError struct {
+	Field string
+	Err   error
+}
+
+func (e *PointError) Error() string {
+	return fmt.Sprintf("invalid point %s: %v", e.Field, e.Err)
+}
+
+func (e *PointError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,51 @@
+package keys
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format selects how keys and proofs are written. Readers detect the format on their own.
+type Format int
+
+const (
+	// FormatJSON writes coordinates as decimal strings
+	FormatJSON Format = iota
+	// FormatBinary writes uncompressed points, the fastest to load
+	FormatBinary
+	// FormatBinaryCompressed writes compressed points, the smallest files
+	FormatBinaryCompressed
+)
+
+// ParseFormat returns the format named "json", "binary" or "compressed"
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "json":
+		return FormatJSON, nil
+	case "binary":
+		return FormatBinary, nil
+	case "compressed":
+		return FormatBinaryCompressed, nil
+	}
+	return FormatJSON, fmt.Errorf("unknown format %q, expected json, binary or compressed", name)
+}
+
+// IsBinary peeks at the magic header, a JSON document can't start with it
+func IsBinary(br *bufio.Reader) bool {
+	magic, err := br.Peek(len(binaryMagic))
+	return err == nil && bytes.Equal(magic, binaryMagic)
+}
+
+// WriteJSON writes the indented JSON form of a key or proof
+func WriteJSON(w io.Writer, data interface{}) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	_, err = w.Write(jsonData)
+	return err
+}
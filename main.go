@@ -1,11 +1,26 @@
 package main 
 
 import (
-	"r1cs-zk-go/trusted_setup"
-	"r1cs-zk-go/prover"
-	"r1cs-zk-go/verifier"
+	"r1cs-zk-go/checker"
+	"r1cs-zk-go/circ"
+	"r1cs-zk-go/groth16"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/iden3"
+	"r1cs-zk-go/keys"
+	"r1cs-zk-go/snarkjs"
+	"r1cs-zk-go/solidity"
+	"r1cs-zk-go/utils"
+	"r1cs-zk-go/witness"
+	"github.com/consensys/gnark-crypto/ecc"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 func main() {
@@ -16,18 +31,63 @@ func main() {
 	}
 
 	command := os.Args[1]
+	args := os.Args[2:]
 
 	switch command {
 	case "setup":
-		trusted_setup.GenerateSRS()
+		p := parseFlags(command, args, "r1cs", "pk", "vk", "out", "format", "curve")
+		setup(p)
 	case "prove":
-		prover.Prove()
+		p := parseFlags(command, args, "r1cs", "witness", "pk", "proof", "public", "out", "format")
+		prove(p)
+	case "check":
+		p := parseFlags(command, args, "r1cs", "witness", "curve")
+		if !check(p) {
+			fmt.Println("The witness does not satisfy the R1CS!")
+			os.Exit(1)
+		}
+		fmt.Println("The witness satisfies the R1CS!")
 	case "verify":
-		if !verifier.VerifyProof() {
+		p := parseFlags(command, args, "vk", "proof", "public", "batch")
+		if p.batch {
+			if p.args.NArg() == 0 || p.args.NArg() % 2 != 0 {
+				fail("verify --batch expects <proof> <public> pairs")
+			}
+			if !verifyBatch(p, p.args.Args()) {
+				fmt.Println("Invalid Proofs!")
+				os.Exit(1)
+			}
+			fmt.Println("Valid Proofs!")
+			return
+		}
+		if !verify(p) {
 			fmt.Println("Invalid Proof!")
-		}else {
-			fmt.Println("Valid Proof!")
+			os.Exit(1)
+		}
+		fmt.Println("Valid Proof!")
+	case "export":
+		p := parseFlags(command, args, "r1cs", "witness", "vk", "proof", "public", "out", "format", "curve")
+		if p.interop == "iden3" {
+			exportIden3(p)
+			return
+		}
+		export(p)
+	case "import":
+		p := parseFlags(command, args, "in", "vk", "proof", "public", "out", "format")
+		importFiles(p)
+	case "export-verifier":
+		p := parseFlags(command, args, "vk", "proof", "public", "out", "solidity", "calldata")
+		exportVerifier(p)
+	case "compile":
+		p := parseFlags(command, args, "r1cs", "witness", "inputs", "out", "curve")
+		compile(p)
+	case "ceremony":
+		if len(args) < 2 {
+			fail("ceremony expects a phase and a step, e.g. ceremony phase1 contribute <in> <out>")
 		}
+		ceremony(args[0], args[1], args[2:])
+	case "help", "-h", "--help":
+		printUsage()
 	default:
 		fmt.Println("Unkown command")
 		printUsage()
@@ -35,13 +95,753 @@ func main() {
 	}
 }
 
+// paths holds the files a command reads and writes, "-" stands for stdin/stdout
+type paths struct {
+	r1cs, witness, pk, vk, proof, public string
+	// inputs are the values of the named inputs of a .circ file
+	inputs string
+	// out is the directory outputs are written to when their path isn't given
+	out string
+	// in is the directory of the files to import
+	in     string
+	format groth16.Format
+	// curve is the curve of the keys made by setup and the field of check and export --format iden3,
+	// the other commands use the curve recorded in the keys
+	curve  ecc.ID
+	// interop is the external format of export and import
+	interop  string
+	batch    bool
+	// power, beacon and iterations configure the ceremony steps
+	power      int
+	beacon     string
+	iterations int
+	// solidity and calldata select what export-verifier writes
+	solidity bool
+	calldata bool
+	args     *flag.FlagSet
+	explicit map[string]bool
+}
+
+// status is where progress messages go, stderr when an output is written to stdout
+var status io.Writer = os.Stdout
+
+var flagUsages = map[string]string{
+	"r1cs":    "R1CS file, JSON or circom .r1cs (default r1cs.json)",
+	"witness": "witness file, JSON or circom .wtns (default witness.json)",
+	"pk":      "proving key file (default pk.json)",
+	"vk":      "verifying key file (default vk.json)",
+	"proof":   "proof file (default proof.json)",
+	"public":  "public inputs file (default public.json)",
+	"inputs":  "values of the inputs of the circuit by name, the witness is written when given (default inputs.json if it exists)",
+	"out":     "directory the outputs are written to",
+	"in":      "directory the snarkjs files are imported from (default snarkjs)",
+	"format":  "format of the keys and proof written: json, binary or compressed (default json)",
+	"curve":   "curve: bn254, bls12-377, bls12-381 or bw6-761 (default bls12-381)",
+}
+
+// parseFlags parses the flags of command, only the given flag names are accepted.
+// Outputs that are not given explicitly are written to --out.
+func parseFlags(command string, args []string, names ...string) *paths {
+	p := &paths{}
+	format := "json"
+	curveName := groth16.DefaultCurve.String()
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	targets := map[string]*string{
+		"r1cs": &p.r1cs, "witness": &p.witness, "pk": &p.pk, "vk": &p.vk,
+		"proof": &p.proof, "public": &p.public, "out": &p.out, "in": &p.in, "inputs": &p.inputs,
+	}
+	interop := command == "export" || command == "import"
+	for _, name := range names {
+		if name == "format" && interop {
+			fs.StringVar(&p.interop, "format", "snarkjs", "external format: snarkjs, or iden3 to export the circuit and witness")
+			continue
+		}
+		if name == "format" {
+			fs.StringVar(&format, "format", "json", flagUsages[name])
+			continue
+		}
+		if name == "curve" {
+			fs.StringVar(&curveName, "curve", curveName, flagUsages[name])
+			continue
+		}
+		if name == "power" {
+			fs.IntVar(&p.power, "power", 10, "the ceremony serves circuits of up to 2^power constraints")
+			continue
+		}
+		if name == "beacon" {
+			fs.StringVar(&p.beacon, "beacon", "", "hex encoded public random value the beacon secrets are derived from, e.g. a future block hash")
+			continue
+		}
+		if name == "iterations" {
+			fs.IntVar(&p.iterations, "iterations", 10, "the beacon is hashed 2^iterations times")
+			continue
+		}
+		if name == "batch" {
+			fs.BoolVar(&p.batch, "batch", false, "verify the <proof> <public> pairs given as arguments with a single multi-pairing")
+			continue
+		}
+		if name == "solidity" {
+			fs.BoolVar(&p.solidity, "solidity", false, "write a Solidity verifier contract, Verifier.sol, for the verifying key")
+			continue
+		}
+		if name == "calldata" {
+			fs.BoolVar(&p.calldata, "calldata", false, "print the calldata of verifyProof for the proof and its public inputs")
+			continue
+		}
+		fs.StringVar(targets[name], name, "", flagUsages[name])
+	}
+	fs.Parse(args)
+	p.args = fs
+	p.explicit = map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { p.explicit[f.Name] = true })
+
+	var err error
+	if p.format, err = groth16.ParseFormat(format); err != nil {
+		fail("%v", err)
+	}
+	if p.curve, err = groth16.ParseCurve(curveName); err != nil {
+		fail("%v", err)
+	}
+	if interop && p.interop != "snarkjs" && !(command == "export" && p.interop == "iden3") {
+		fail("unknown format %q, expected snarkjs (or iden3 for export, circom files are read directly by --r1cs and --witness)", p.interop)
+	}
+
+	outputs := map[string]bool{}
+	switch command {
+	case "setup", "ceremony phase2 keys":
+		outputs = map[string]bool{"pk": true, "vk": true}
+	case "prove":
+		outputs = map[string]bool{"proof": true, "public": true}
+	case "import":
+		outputs = map[string]bool{"vk": true, "proof": true, "public": true}
+	case "compile":
+		outputs = map[string]bool{"r1cs": true, "witness": true}
+	}
+
+	nbStdin, nbStdout := 0, 0
+	for name, target := range targets {
+		if name == "out" || name == "in" {
+			continue
+		}
+		if *target == "" {
+			*target = name + ".json"
+			if outputs[name] && p.out != "" {
+				*target = filepath.Join(p.out, *target)
+			}
+		}
+		if *target == "-" && outputs[name] {
+			nbStdout++
+		} else if *target == "-" {
+			nbStdin++
+		}
+	}
+	if nbStdin > 1 {
+		fail("Only one input can be read from stdin")
+	}
+	if nbStdout > 1 {
+		fail("Only one output can be written to stdout")
+	}
+	if nbStdout == 1 {
+		status = os.Stderr
+	}
+
+	return p
+}
+
+func setup(p *paths) {
+	r1csData := loadR1CS(p.r1cs, p.curve)
+
+	pk, vk, err := groth16.Setup(p.curve, r1csData)
+	if err != nil {
+		fail("Trusted setup failed: %v", err)
+	}
+
+	save(p.pk, "Proving key", func(w io.Writer) error { return groth16.WriteProvingKey(w, pk, p.format) })
+	save(p.vk, "Verifying key", func(w io.Writer) error { return groth16.WriteVerifyingKey(w, vk, p.format) })
+}
+
+func prove(p *paths) {
+	var pk groth16.ProvingKey
+	load(p.pk, "make sure you have ran the trusted setup", func(r io.Reader) (err error) {
+		pk, err = groth16.ReadProvingKey(r)
+		return
+	})
+	r1csData := loadR1CS(p.r1cs, pk.Curve())
+	witnessData := loadWitness(p.witness, r1csData, pk.Curve())
+
+	proof, err := groth16.Prove(pk, r1csData, witnessData)
+	if err != nil {
+		fail("Failed to generate the proof: %v", err)
+	}
+
+	save(p.proof, "Proof", func(w io.Writer) error { return groth16.WriteProof(w, proof, p.format) })
+	// the verifier only gets the public part of the witness, in the field of the curve
+	publicInputs := utils.Reduce(witnessData.PublicInputs, pk.Curve().ScalarField())
+	save(p.public, "Public inputs", func(w io.Writer) error { return groth16.WritePublicInputs(w, publicInputs) })
+}
+
+// check prints every constraint violated by the witness and returns whether it satisfies the R1CS
+func check(p *paths) bool {
+	r1csData := loadR1CS(p.r1cs, p.curve)
+	witnessData := loadWitness(p.witness, r1csData, p.curve)
+
+	violations, err := checker.Check(r1csData, witnessData, p.curve.ScalarField())
+	if err != nil {
+		fmt.Printf("Malformed witness: %v\n", err)
+		return false
+	}
+
+	for _, v := range violations {
+		fmt.Println(v)
+	}
+
+	return len(violations) == 0
+}
+
+func verify(p *paths) bool {
+	vk := loadVerifyingKey(p.vk)
+
+	// a proof with invalid points has to be rejected rather than aborting
+	proof, err := readProof(p.proof)
+	var pointErr *keys.PointError
+	if errors.As(err, &pointErr) {
+		fmt.Printf("Malformed proof: %v\n", err)
+		return false
+	}
+	if err != nil {
+		fail("%v", err)
+	}
+
+	var publicInputs []*big.Int
+	load(p.public, "make sure you have ran the prove command", func(r io.Reader) (err error) {
+		publicInputs, err = groth16.ReadPublicInputs(r)
+		return
+	})
+
+	ok, err := groth16.Verify(vk, proof, publicInputs)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+
+	return ok
+}
+
+// export writes the verifying key, and the proof with its public inputs when there is one, in snarkjs's layout
+func export(p *paths) {
+	out := p.out
+	if out == "" {
+		out = "snarkjs"
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		fail("Failed to create %s: %v", out, err)
+	}
+
+	vk := onlyBLS12381("snarkjs", loadVerifyingKey(p.vk)).(bls12381.VerifyingKey)
+	save(filepath.Join(out, "verification_key.json"), "snarkjs verification key", func(w io.Writer) error { return snarkjs.WriteVerificationKey(w, vk) })
+
+	// the proof is optional, exporting the verifying key alone is enough to verify in JavaScript
+	if !p.explicit["proof"] && !exists(p.proof) {
+		return
+	}
+	proof, err := readProof(p.proof)
+	if err != nil {
+		fail("invalid %s: %v", p.proof, err)
+	}
+	var publicInputs []*big.Int
+	load(p.public, "make sure you have ran the prove command", func(r io.Reader) (err error) {
+		publicInputs, err = groth16.ReadPublicInputs(r)
+		return
+	})
+
+	bls12381Proof := onlyBLS12381("snarkjs", proof).(bls12381.Proof)
+	save(filepath.Join(out, "proof.json"), "snarkjs proof", func(w io.Writer) error { return snarkjs.WriteProof(w, bls12381Proof) })
+	save(filepath.Join(out, "public.json"), "snarkjs public signals", func(w io.Writer) error { return snarkjs.WritePublicInputs(w, publicInputs) })
+}
+
+// exportIden3 writes the R1CS, and the witness when there is one, as circom's .r1cs and .wtns files
+func exportIden3(p *paths) {
+	out := p.out
+	if out == "" {
+		out = "iden3"
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		fail("Failed to create %s: %v", out, err)
+	}
+
+	r1csData := loadR1CS(p.r1cs, p.curve)
+	save(filepath.Join(out, "circuit.r1cs"), "circom R1CS", func(w io.Writer) error { return iden3.WriteR1CS(w, r1csData, p.curve) })
+
+	if !p.explicit["witness"] && !exists(p.witness) {
+		return
+	}
+	witnessData := loadWitness(p.witness, r1csData, p.curve)
+	save(filepath.Join(out, "witness.wtns"), "circom witness", func(w io.Writer) error { return iden3.WriteWitness(w, witnessData, p.curve) })
+}
+
+// exportVerifier writes the Solidity verifier contract or prints the calldata verifying a proof with it
+func exportVerifier(p *paths) {
+	if p.solidity == p.calldata {
+		fail("export-verifier expects either --solidity or --calldata")
+	}
+
+	if p.solidity {
+		vk := onlyBLS12381("The Solidity verifier", loadVerifyingKey(p.vk)).(bls12381.VerifyingKey)
+		path := filepath.Join(p.out, "Verifier.sol")
+		save(path, "Solidity verifier", func(w io.Writer) error { return solidity.WriteVerifier(w, vk) })
+		return
+	}
+
+	proof, err := readProof(p.proof)
+	if err != nil {
+		fail("invalid %s: %v", p.proof, err)
+	}
+	var publicInputs []*big.Int
+	load(p.public, "make sure you have ran the prove command", func(r io.Reader) (err error) {
+		publicInputs, err = groth16.ReadPublicInputs(r)
+		return
+	})
+
+	calldata, err := solidity.Calldata(onlyBLS12381("The Solidity verifier", proof).(bls12381.Proof), publicInputs)
+	if err != nil {
+		fail("%v", err)
+	}
+	fmt.Printf("0x%x\n", calldata)
+}
+
+// importFiles converts the snarkjs files found in --in, the proof and public signals being optional
+func importFiles(p *paths) {
+	in := p.in
+	if in == "" {
+		in = "snarkjs"
+	}
+
+	var vk groth16.VerifyingKey
+	load(filepath.Join(in, "verification_key.json"), "", func(r io.Reader) (err error) {
+		vk, err = snarkjs.ReadVerificationKey(r)
+		return
+	})
+	save(p.vk, "Verifying key", func(w io.Writer) error { return groth16.WriteVerifyingKey(w, vk, groth16.FormatJSON) })
+
+	proofPath := filepath.Join(in, "proof.json")
+	if !exists(proofPath) {
+		return
+	}
+	var proof groth16.Proof
+	load(proofPath, "", func(r io.Reader) (err error) {
+		proof, err = snarkjs.ReadProof(r)
+		return
+	})
+	var publicInputs []*big.Int
+	load(filepath.Join(in, "public.json"), "", func(r io.Reader) (err error) {
+		publicInputs, err = snarkjs.ReadPublicInputs(r)
+		return
+	})
+
+	save(p.proof, "Proof", func(w io.Writer) error { return groth16.WriteProof(w, proof, groth16.FormatJSON) })
+	save(p.public, "Public inputs", func(w io.Writer) error { return groth16.WritePublicInputs(w, publicInputs) })
+}
+
+// compile compiles the .circ file given as argument to the dense R1CS and, when the values of its inputs are
+// given, solves its witness on the field of --curve
+func compile(p *paths) {
+	if p.args.NArg() != 1 {
+		fail("compile expects <circuit.circ>")
+	}
+	path := p.args.Arg(0)
+
+	var src []byte
+	load(path, "", func(r io.Reader) (err error) {
+		src, err = io.ReadAll(r)
+		return
+	})
+	// errors are prefixed with the position in the source, path:line:column, like a compiler's
+	circuit, err := circ.Compile(string(src))
+	var circErr *circ.Error
+	if errors.As(err, &circErr) {
+		fail("%s:%v", path, err)
+	}
+	if err != nil {
+		fail("%s: %v", path, err)
+	}
+	fmt.Fprintf(status, "%d constraints, %d wires, public inputs: %s\n", circuit.R1CS.NbConstraints(), circuit.R1CS.NbVariables, strings.Join(circuit.Public, ", "))
+	save(p.r1cs, "R1CS", circuit.R1CS.WriteDenseJSON)
+
+	if !p.explicit["inputs"] && !exists(p.inputs) {
+		return
+	}
+	var inputs map[string]*big.Int
+	load(p.inputs, "", func(r io.Reader) (err error) {
+		inputs, err = witness.ReadInputs(r)
+		return
+	})
+	// the constraints the inputs break are reported at their position in the source, unknown or missing inputs
+	// are errors of the inputs file
+	witnessData, err := circuit.Solve(inputs, p.curve.ScalarField())
+	if errors.As(err, &circErr) {
+		fail("%s:%v", path, err)
+	}
+	if err != nil {
+		fail("invalid %s: %v", p.inputs, err)
+	}
+	save(p.witness, "Witness", func(w io.Writer) error { return witness.WriteWitness(w, witnessData) })
+}
+
+// ceremony runs a step of the trusted setup ceremony. Every step reads the file left by the previous one
+// and writes a new one, so that participants can run locally and exchange files.
+func ceremony(phase, step string, args []string) {
+	command := "ceremony " + phase + " " + step
+	switch phase + " " + step {
+	case "phase1 new":
+		p := parseFlags(command, args, "curve", "power", "format")
+		files := ceremonyFiles(p, "<out>")
+		phase1, err := groth16.NewPhase1(p.curve, p.power)
+		if err != nil {
+			fail("%v", err)
+		}
+		save(files[0], "Phase 1", func(w io.Writer) error { return groth16.WritePhase1(w, phase1, p.format) })
+	case "phase1 contribute", "phase2 contribute":
+		p := parseFlags(command, args, "format")
+		files := ceremonyFiles(p, "<in>", "<out>")
+		state, what, write := loadCeremony(phase, files[0], p.format)
+		if err := state.Contribute(); err != nil {
+			fail("Failed to contribute: %v", err)
+		}
+		save(files[1], what, write)
+		fmt.Fprintf(status, "Contribution %d, transcript hash %x\n", state.NbContributions(), state.Hash())
+	case "phase1 beacon", "phase2 beacon":
+		p := parseFlags(command, args, "format", "beacon", "iterations")
+		files := ceremonyFiles(p, "<in>", "<out>")
+		beacon, err := hex.DecodeString(p.beacon)
+		if err != nil || len(beacon) == 0 {
+			fail("--beacon must be a non empty hex string")
+		}
+		state, what, write := loadCeremony(phase, files[0], p.format)
+		if err := state.Beacon(beacon, p.iterations); err != nil {
+			fail("Failed to apply the beacon: %v", err)
+		}
+		save(files[1], what, write)
+		fmt.Fprintf(status, "Beacon contribution %d, transcript hash %x\n", state.NbContributions(), state.Hash())
+	case "phase1 verify":
+		p := parseFlags(command, args)
+		files := ceremonyFiles(p, "<in>")
+		phase1 := loadPhase1(files[0])
+		if err := phase1.Verify(); err != nil {
+			fmt.Printf("Invalid phase 1: %v\n", err)
+			os.Exit(1)
+		}
+		if phase1.NbContributions() == 0 {
+			fmt.Println("The phase 1 has no contribution yet, its secrets are known")
+		}
+		fmt.Printf("Valid phase 1 on %s for up to %d constraints, %d contributions, transcript hash %x\n", phase1.Curve(), phase1.Size(), phase1.NbContributions(), phase1.Hash())
+	case "phase2 new":
+		p := parseFlags(command, args, "r1cs", "format")
+		files := ceremonyFiles(p, "<phase1>", "<out>")
+		phase1, r1csData := loadPhase2Sources(files[0], p.r1cs)
+		// a .ptau file doesn't carry its transcript, snarkjs checks it
+		if phase1.NbContributions() == 0 && !strings.HasSuffix(files[0], ".ptau") {
+			fail("The phase 1 has no contribution yet, its secrets are known and anyone could forge proofs with the keys")
+		}
+		phase2, err := groth16.NewPhase2(phase1, r1csData)
+		if err != nil {
+			fail("%v", err)
+		}
+		save(files[1], "Phase 2", func(w io.Writer) error { return groth16.WritePhase2(w, phase2, p.format) })
+	case "phase2 verify":
+		p := parseFlags(command, args, "r1cs")
+		files := ceremonyFiles(p, "<phase1>", "<in>")
+		phase1, r1csData := loadPhase2Sources(files[0], p.r1cs)
+		phase2 := loadPhase2(files[1])
+		if err := groth16.VerifyPhase2(phase2, phase1, r1csData); err != nil {
+			fmt.Printf("Invalid phase 2: %v\n", err)
+			os.Exit(1)
+		}
+		if phase2.NbContributions() == 0 {
+			fmt.Println("The phase 2 has no contribution yet, its secrets are known")
+		}
+		fmt.Printf("Valid phase 2 on %s, %d contributions, transcript hash %x\n", phase2.Curve(), phase2.NbContributions(), phase2.Hash())
+	case "phase2 keys":
+		p := parseFlags(command, args, "pk", "vk", "out", "format")
+		files := ceremonyFiles(p, "<in>")
+		phase2 := loadPhase2(files[0])
+		if phase2.NbContributions() == 0 {
+			fail("The phase 2 has no contribution yet, anyone could forge proofs with its keys")
+		}
+		pk, vk, err := groth16.Phase2Keys(phase2)
+		if err != nil {
+			fail("%v", err)
+		}
+		save(p.pk, "Proving key", func(w io.Writer) error { return groth16.WriteProvingKey(w, pk, p.format) })
+		save(p.vk, "Verifying key", func(w io.Writer) error { return groth16.WriteVerifyingKey(w, vk, p.format) })
+	default:
+		fail("unknown ceremony step %q, expected phase1 new, contribute, verify or beacon, or phase2 new, contribute, verify, beacon or keys", phase + " " + step)
+	}
+}
+
+// ceremonyFiles returns the file arguments of a ceremony step, named after names in the error message
+func ceremonyFiles(p *paths, names ...string) []string {
+	if p.args.NArg() != len(names) {
+		fail("%s expects %s", p.args.Name(), strings.Join(names, " "))
+	}
+	files := p.args.Args()
+	// the output, when there is one, is the last file
+	if len(files) > 1 && files[len(files)-1] == "-" || len(names) == 1 && names[0] == "<out>" && files[0] == "-" {
+		status = os.Stderr
+	}
+	return files
+}
+
+// ceremonyState is what the contribute and beacon steps do on a phase 1 or a phase 2
+type ceremonyState interface {
+	NbContributions() int
+	Contribute() error
+	Beacon(beacon []byte, iterations int) error
+	Hash() []byte
+}
+
+// loadCeremony reads the file of a ceremony phase and returns it, along with its name and how to write it back
+func loadCeremony(phase, path string, format groth16.Format) (ceremonyState, string, func(io.Writer) error) {
+	if phase == "phase1" {
+		phase1 := loadPhase1(path)
+		return phase1, "Phase 1", func(w io.Writer) error { return groth16.WritePhase1(w, phase1, format) }
+	}
+	phase2 := loadPhase2(path)
+	return phase2, "Phase 2", func(w io.Writer) error { return groth16.WritePhase2(w, phase2, format) }
+}
+
+func loadPhase1(path string) groth16.Phase1 {
+	var phase1 groth16.Phase1
+	load(path, "", func(r io.Reader) (err error) {
+		phase1, err = groth16.ReadPhase1(r)
+		return
+	})
+	return phase1
+}
+
+// loadPhase2Sources reads and checks the phase 1 a phase 2 is derived from, and the R1CS on its curve.
+// A .ptau file from a snarkjs ceremony is read on BLS12-381 up to the powers the R1CS needs, its transcript
+// being left to snarkjs powersoftau verify.
+func loadPhase2Sources(phase1Path, r1csPath string) (groth16.Phase1, groth16.R1CS) {
+	if strings.HasSuffix(phase1Path, ".ptau") {
+		r1csData := loadR1CS(r1csPath, ecc.BLS12_381)
+		var phase1 groth16.Phase1
+		load(phase1Path, "", func(r io.Reader) (err error) {
+			phase1, err = snarkjs.ReadPtau(r, snarkjs.PtauPower(r1csData.NbConstraints()))
+			return
+		})
+		return phase1, r1csData
+	}
+
+	phase1 := loadPhase1(phase1Path)
+	if err := phase1.Verify(); err != nil {
+		fail("Invalid phase 1: %v", err)
+	}
+	return phase1, loadR1CS(r1csPath, phase1.Curve())
+}
+
+func loadPhase2(path string) groth16.Phase2 {
+	var phase2 groth16.Phase2
+	load(path, "", func(r io.Reader) (err error) {
+		phase2, err = groth16.ReadPhase2(r)
+		return
+	})
+	return phase2
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// verifyBatch verifies the (proof, public inputs) file pairs with the verifying key and prints which proofs are invalid
+func verifyBatch(p *paths, files []string) bool {
+	vk := loadVerifyingKey(p.vk)
+
+	nbProofs := len(files) / 2
+	items := make([]groth16.BatchItem, 0, nbProofs)
+	itemFiles := make([]int, 0, nbProofs)
+	allValid := true
+	for i := 0; i < len(files); i += 2 {
+		item, err := loadBatchItem(vk, files[i], files[i+1])
+		if err != nil {
+			fmt.Printf("Invalid proof %s (%s): %v\n", files[i], files[i+1], err)
+			allValid = false
+			continue
+		}
+		items = append(items, item)
+		itemFiles = append(itemFiles, i)
+	}
+
+	invalid, err := groth16.VerifyBatch(vk, items)
+	if err != nil {
+		fmt.Printf("Batch verification failed: %v\n", err)
+		return false
+	}
+	for _, i := range invalid {
+		fmt.Printf("Invalid proof %s (%s)\n", files[itemFiles[i]], files[itemFiles[i]+1])
+	}
+
+	fmt.Printf("%d/%d proofs are valid\n", len(items) - len(invalid), nbProofs)
+	return allValid && len(invalid) == 0
+}
+
+func loadBatchItem(vk groth16.VerifyingKey, proofPath, publicPath string) (groth16.BatchItem, error) {
+	proof, err := readProof(proofPath)
+	if err != nil {
+		return groth16.BatchItem{}, err
+	}
+	if proof.Curve() != vk.Curve() {
+		return groth16.BatchItem{}, fmt.Errorf("the proof was made on %s but the verifying key on %s", proof.Curve(), vk.Curve())
+	}
+
+	f, err := open(publicPath)
+	if err != nil {
+		return groth16.BatchItem{}, err
+	}
+	defer f.Close()
+	publicInputs, err := groth16.ReadPublicInputs(f)
+	if err != nil {
+		return groth16.BatchItem{}, err
+	}
+
+	return groth16.BatchItem{Proof: proof, PublicInputs: publicInputs}, nil
+}
+
+func readProof(path string) (groth16.Proof, error) {
+	f, err := open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read %s, make sure you have ran the prove command", path)
+	}
+	defer f.Close()
+
+	return groth16.ReadProof(f)
+}
+
+func loadR1CS(path string, curveID ecc.ID) groth16.R1CS {
+	var r1csData groth16.R1CS
+	load(path, "", func(r io.Reader) (err error) {
+		r1csData, err = groth16.ReadR1CS(r, curveID)
+		return
+	})
+	return r1csData
+}
+
+func loadWitness(path string, r1csData groth16.R1CS, curveID ecc.ID) groth16.Witness {
+	var witnessData groth16.Witness
+	load(path, "", func(r io.Reader) (err error) {
+		witnessData, err = groth16.ReadWitness(r, r1csData.NbPublicInputs, curveID)
+		return
+	})
+	return witnessData
+}
+
+func loadVerifyingKey(path string) groth16.VerifyingKey {
+	var vk groth16.VerifyingKey
+	load(path, "make sure you have ran the trusted setup", func(r io.Reader) (err error) {
+		vk, err = groth16.ReadVerifyingKey(r)
+		return
+	})
+	return vk
+}
+
+// onlyBLS12381 exits when the key or proof wasn't made on BLS12-381, the only curve of snarkjs's files and of
+// the EIP-2537 precompiles, and returns it otherwise
+func onlyBLS12381(what string, v interface{ Curve() ecc.ID }) interface{} {
+	if v.Curve() != ecc.BLS12_381 {
+		fail("%s only supports BLS12-381, the key or proof was made on %s", what, v.Curve())
+	}
+	return v
+}
+
+// open opens path for reading, "-" is stdin
+func open(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// load opens path and parses it with read, exiting with an error message on failure
+func load(path, hint string, read func(io.Reader) error) {
+	f, err := open(path)
+	if err != nil {
+		if hint != "" {
+			fail("Could not read %s, %s", path, hint)
+		}
+		fail("Could not read %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if err := read(f); err != nil {
+		fail("invalid %s: %v", path, err)
+	}
+}
+
+// save creates path, "-" being stdout, and writes it with write, exiting with an error message on failure.
+// The directory of path, e.g. the one of --out, is created when it doesn't exist.
+func save(path, what string, write func(io.Writer) error) {
+	if path == "-" {
+		if err := write(os.Stdout); err != nil {
+			fail("Failed to write %s: %v", what, err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fail("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fail("Failed to create %s: %v", path, err)
+	}
+
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fail("Failed to write %s: %v", path, err)
+	}
+	fmt.Fprintf(status, "%s saved to %s\n", what, path)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format + "\n", args...)
+	os.Exit(1)
+}
+
 func printUsage() {
-	fmt.Println("Usage: go build && ./r1cs-zk-go <command>")
+	fmt.Println("Usage: go build && ./r1cs-zk-go <command> [flags]")
 	fmt.Println("")
 	fmt.Println("Commands:")
-	fmt.Println("  setup    Run trusted setup and generate proving/verifying keys")
-	fmt.Println("  prove    Generate a Groth16 zk proof using 'pk.json' and save it to 'proof.json' file")
-	fmt.Println("  verify   Verify a Groth16 zk proof from 'proof.json' and 'vk.json' file ")
+	fmt.Println("  setup    Run trusted setup and generate proving/verifying keys           [--r1cs] [--pk] [--vk] [--out] [--format] [--curve]")
+	fmt.Println("  prove    Generate a Groth16 zk proof and the public inputs to verify it  [--r1cs] [--witness] [--pk] [--proof] [--public] [--out] [--format]")
+	fmt.Println("  check    Check that the witness satisfies every constraint of the R1CS   [--r1cs] [--witness] [--curve]")
+	fmt.Println("  verify   Verify a Groth16 zk proof, exits with 1 if it is invalid        [--vk] [--proof] [--public]")
+	fmt.Println("           verify --batch <proof> <public> [<proof> <public> ...] verifies many proofs at once")
+	fmt.Println("  export   Write the verifying key, proof and public inputs in snarkjs's layout [--format snarkjs] [--vk] [--proof] [--public] [--out]")
+	fmt.Println("           export --format iden3 writes the R1CS and witness as circom's .r1cs/.wtns  [--r1cs] [--witness] [--out] [--curve]")
+	fmt.Println("  export-verifier --solidity  Write a Solidity verifier using the EIP-2537 precompiles   [--vk] [--out]")
+	fmt.Println("  export-verifier --calldata  Print the calldata verifying the proof with the contract   [--proof] [--public]")
+	fmt.Println("  ceremony phase1 new <out>             Start a powers of tau ceremony     [--curve] [--power] [--format]")
+	fmt.Println("  ceremony phase1 contribute <in> <out> Add a contribution with fresh secrets [--format]")
+	fmt.Println("  ceremony phase1 beacon <in> <out>     Close the ceremony with a public random beacon [--beacon] [--iterations] [--format]")
+	fmt.Println("  ceremony phase1 verify <in>           Check every contribution of the ceremony")
+	fmt.Println("  ceremony phase2 new <phase1> <out>    Derive the keys of the R1CS from a verified phase 1 [--r1cs] [--format]")
+	fmt.Println("                                        <phase1> can be a snarkjs .ptau file, on BLS12-381")
+	fmt.Println("  ceremony phase2 contribute <in> <out> Add a contribution to teta with a fresh secret [--format]")
+	fmt.Println("  ceremony phase2 beacon <in> <out>     Close the phase 2 with a public random beacon [--beacon] [--iterations] [--format]")
+	fmt.Println("  ceremony phase2 verify <phase1> <in>  Check the phase 1 (the powers only for a .ptau) and every contribution of the phase 2 [--r1cs]")
+	fmt.Println("  ceremony phase2 keys <in>             Write the proving and verifying keys of the phase 2 [--pk] [--vk] [--out] [--format]")
+	fmt.Println("  compile <circuit.circ>  Compile a .circ circuit to the dense R1CS, and its witness from --inputs  [--r1cs] [--witness] [--inputs] [--out] [--curve]")
+	fmt.Println("  import   Convert snarkjs's files found in --in back                        [--format snarkjs] [--in] [--vk] [--proof] [--public] [--out]")
+	fmt.Println("")
+	fmt.Println("Flags:")
+	fmt.Println("  --r1cs, --witness, --pk, --vk, --proof, --public  file paths, default to r1cs.json, witness.json, ...")
+	fmt.Println("                                                    '-' reads from stdin or writes to stdout")
+	fmt.Println("  --out <dir>                                       directory the outputs are written to when their path isn't given")
+	fmt.Println("  --format json|binary|compressed                   format of the keys and proof written, read back automatically")
+	fmt.Println("  --curve bn254|bls12-377|bls12-381|bw6-761         curve of the keys, recorded in them, the default is bls12-381")
 	fmt.Println("")
 	fmt.Println("Description:")
 	fmt.Println("  This program implements a Groth16 zero-knowledge proof system")
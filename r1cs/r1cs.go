@@ -3,87 +3,319 @@ package r1cs
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"gitlab.com/oelmekki/matrix"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"math/big"
+	"r1cs-zk-go/utils"
 )
 
+// Term is a single coefficient * a[Wire] of a linear combination
+type Term struct {
+	Wire  int
+	Coeff *big.Int
+}
+
+// LinearCombination is a sparse row of one of the L, R and O matrices. Terms are sorted by wire index.
+type LinearCombination []Term
+
+// Constraint is a single row of the R1CS: (L·a) * (R·a) = O·a
+type Constraint struct {
+	L LinearCombination `json:"L"`
+	R LinearCombination `json:"R"`
+	O LinearCombination `json:"O"`
+}
+
+// R1CSData holds the constraints in sparse form, only the nonzero coefficients are stored.
+// Coefficients can be written in the JSON file as numbers or as decimal/hex ("0x...") strings.
+// An R1CS doesn't depend on the curve: coefficients are kept as integers and reduced mod r,
+// negative values included, once the curve is known.
+//
+// Two JSON layouts are accepted:
+//   - dense:  {"nbPublicInputs": 2, "L": [[...]], "R": [[...]], "O": [[...]]}
+//   - sparse: {"nbPublicInputs": 2, "nbVariables": 4, "constraints": [{"L": {"3": "1"}, "R": {"3": "1"}, "O": {"2": "1"}}, ...]}
+// In the sparse layout "nbVariables" is optional and defaults to the highest wire index + 1.
+// "nbPublicInputs" is required: the first nbPublicInputs entries of the witness (the constant 1 included) are public.
 type R1CSData struct {
-	L [][]int `json:"L"`
-	R [][]int `json:"R"`
-	O [][]int `json:"O"`
+	Constraints    []Constraint
+	NbVariables    int
+	NbPublicInputs int
 }
 
-// LoadR1CSFromJSON reads and parses the R1CS JSON file
-func LoadR1CSFromJSON() (matrix.Matrix, matrix.Matrix, matrix.Matrix, error) {
-	jsonData, err := ioutil.ReadFile("r1cs.json")
+// LoadR1CSFromJSON reads and parses the R1CS JSON file at path
+func LoadR1CSFromJSON(path string) (R1CSData, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("failed to read R1CS file: %v", err)
+		return R1CSData{}, fmt.Errorf("failed to read R1CS file: %v", err)
 	}
+	defer f.Close()
 
+	return ReadR1CS(f)
+}
+
+// ReadR1CS parses an R1CS JSON document from r
+func ReadR1CS(r io.Reader) (R1CSData, error) {
 	var r1csData R1CSData
-	err = json.Unmarshal(jsonData, &r1csData)
+	err := json.NewDecoder(r).Decode(&r1csData)
 	if err != nil {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("failed to parse R1CS JSON: %v", err)
+		return R1CSData{}, fmt.Errorf("failed to parse R1CS JSON: %v", err)
 	}
 
-	if len(r1csData.L) != len(r1csData.R) || len(r1csData.R) != len(r1csData.O) {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("R1CS matrices must have the same number of rows")
+	if err := r1csData.Validate(); err != nil {
+		return R1CSData{}, err
 	}
 
-	// sanity checks that all matrices' rows have the same column number
-	if len(r1csData.L) > 0 {
-		lCols := len(r1csData.L[0])
-		for i, row := range r1csData.L {
-			if len(row) != lCols {
-				return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("L matrix row %d has inconsistent column count", i)
+	return r1csData, nil
+}
+
+// Validate checks an R1CS that may have been built in memory rather than parsed:
+// it must have constraints, and every wire must be within the witness of NbVariables entries.
+func (r1csData R1CSData) Validate() error {
+	if len(r1csData.Constraints) == 0 {
+		return fmt.Errorf("R1CS must have at least one constraint")
+	}
+	if r1csData.NbPublicInputs < 0 || r1csData.NbPublicInputs > r1csData.NbVariables {
+		return fmt.Errorf("nbPublicInputs must be between 0 and the number of variables (%d), got %d", r1csData.NbVariables, r1csData.NbPublicInputs)
+	}
+	for i, c := range r1csData.Constraints {
+		for _, lc := range []LinearCombination{c.L, c.R, c.O} {
+			for _, t := range lc {
+				if t.Wire < 0 || t.Wire >= r1csData.NbVariables {
+					return fmt.Errorf("constraint %d references wire %d but there are %d variables", i, t.Wire, r1csData.NbVariables)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// NbConstraints returns the number of constraints (rows)
+func (r1csData R1CSData) NbConstraints() int {
+	return len(r1csData.Constraints)
+}
+
+// Eval multiplies out L·a, R·a and O·a modulo r for the witness vector a
+func (r1csData R1CSData) Eval(a []*big.Int, r *big.Int) ([]*big.Int, []*big.Int, []*big.Int, error) {
+	if len(a) != r1csData.NbVariables {
+		return nil, nil, nil, fmt.Errorf("expected a witness of size %d, got %d", r1csData.NbVariables, len(a))
+	}
+
+	n := r1csData.NbConstraints()
+	La := make([]*big.Int, n)
+	Ra := make([]*big.Int, n)
+	Oa := make([]*big.Int, n)
+	for i, c := range r1csData.Constraints {
+		La[i] = c.L.Eval(a, r)
+		Ra[i] = c.R.Eval(a, r)
+		Oa[i] = c.O.Eval(a, r)
+	}
+
+	return La, Ra, Oa, nil
+}
+
+// Eval returns the dot product of the linear combination with the witness vector a, reduced modulo r
+func (lc LinearCombination) Eval(a []*big.Int, r *big.Int) *big.Int {
+	sum := new(big.Int)
+	var tmp big.Int
+	for _, t := range lc {
+		tmp.Mul(t.Coeff, a[t.Wire])
+		sum.Add(sum, &tmp)
+	}
+
+	return sum.Mod(sum, r)
+}
+
+type r1csJSON struct {
+	NbPublicInputs *int           `json:"nbPublicInputs"`
+	L              []utils.Values `json:"L,omitempty"`
+	R              []utils.Values `json:"R,omitempty"`
+	O              []utils.Values `json:"O,omitempty"`
+	NbVariables    int            `json:"nbVariables,omitempty"`
+	Constraints    []Constraint   `json:"constraints,omitempty"`
+}
+
+// MarshalJSON always writes the sparse layout
+func (r1csData R1CSData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r1csJSON{
+		NbPublicInputs: &r1csData.NbPublicInputs,
+		NbVariables:    r1csData.NbVariables,
+		Constraints:    r1csData.Constraints,
+	})
+}
+
+func (r1csData *R1CSData) UnmarshalJSON(data []byte) error {
+	var raw r1csJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	dense := raw.L != nil || raw.R != nil || raw.O != nil
+	if dense && raw.Constraints != nil {
+		return fmt.Errorf("R1CS must use either the dense L/R/O layout or the sparse constraints layout, not both")
+	}
+
+	var constraints []Constraint
+	var nbVariables int
+	if dense {
+		var err error
+		constraints, nbVariables, err = fromDense(raw.L, raw.R, raw.O)
+		if err != nil {
+			return err
+		}
+	} else {
+		constraints, nbVariables = raw.Constraints, sparseNbVariables(raw.Constraints)
+		if raw.NbVariables != 0 {
+			if raw.NbVariables < nbVariables {
+				return fmt.Errorf("constraints reference wire %d but nbVariables is %d", nbVariables-1, raw.NbVariables)
 			}
+			nbVariables = raw.NbVariables
 		}
-		for i, row := range r1csData.R {
-			if len(row) != lCols {
-				return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("R matrix row %d has inconsistent column count", i)
+	}
+
+	// the split between public and private inputs can't be guessed from the constraints
+	if raw.NbPublicInputs == nil {
+		return fmt.Errorf("R1CS must declare nbPublicInputs, the number of public entries at the start of the witness")
+	}
+	if *raw.NbPublicInputs < 0 || *raw.NbPublicInputs > nbVariables {
+		return fmt.Errorf("nbPublicInputs must be between 0 and the number of variables (%d), got %d", nbVariables, *raw.NbPublicInputs)
+	}
+
+	r1csData.Constraints = constraints
+	r1csData.NbVariables = nbVariables
+	r1csData.NbPublicInputs = *raw.NbPublicInputs
+	return nil
+}
+
+// WriteDenseJSON writes the dense L/R/O layout, a row per constraint and a column per wire, laid out like the
+// example r1cs.json. MarshalJSON writes the sparse layout, which is smaller for large circuits.
+func (r1csData R1CSData) WriteDenseJSON(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "{\n  \"nbPublicInputs\": %d", r1csData.NbPublicInputs)
+	for m, matrix := range []string{"L", "R", "O"} {
+		fmt.Fprintf(&sb, ",\n  %q: [", matrix)
+		for i, c := range r1csData.Constraints {
+			lc := [3]LinearCombination{c.L, c.R, c.O}[m]
+			if i > 0 {
+				sb.WriteString(",")
 			}
+			sb.WriteString("\n    " + denseRow(lc, r1csData.NbVariables).Inline())
 		}
-		for i, row := range r1csData.O {
-			if len(row) != lCols {
-				return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("O matrix row %d has inconsistent column count", i)
+		sb.WriteString("\n  ]")
+	}
+	sb.WriteString("\n}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// denseRow returns the linear combination with a zero for every wire it doesn't use
+func denseRow(lc LinearCombination, nbVariables int) utils.Values {
+	row := make(utils.Values, nbVariables)
+	for j := range row {
+		row[j] = new(big.Int)
+	}
+	for _, t := range lc {
+		row[t.Wire] = t.Coeff
+	}
+	return row
+}
+
+// sparseNbVariables returns the highest wire index referenced by the constraints + 1
+func sparseNbVariables(constraints []Constraint) int {
+	nbVariables := 0
+	for _, c := range constraints {
+		for _, lc := range []LinearCombination{c.L, c.R, c.O} {
+			if len(lc) > 0 && lc[len(lc)-1].Wire >= nbVariables {
+				nbVariables = lc[len(lc)-1].Wire + 1
 			}
 		}
 	}
+	return nbVariables
+}
 
-	// Build L matrix
-	L, err := buildMatrixFromIntArray(r1csData.L)
-	if err != nil {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("failed to build L matrix: %v", err)
+func fromDense(L, R, O []utils.Values) ([]Constraint, int, error) {
+	if len(L) != len(R) || len(R) != len(O) {
+		return nil, 0, fmt.Errorf("R1CS matrices must have the same number of rows")
+	}
+	if len(L) == 0 {
+		return nil, 0, nil
 	}
 
-	// Build R matrix
-	R, err := buildMatrixFromIntArray(r1csData.R)
-	if err != nil {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("failed to build R matrix: %v", err)
+	// sanity checks that all matrices' rows have the same column number
+	lCols := len(L[0])
+	for i, row := range L {
+		if len(row) != lCols {
+			return nil, 0, fmt.Errorf("L matrix row %d has inconsistent column count", i)
+		}
+	}
+	for i, row := range R {
+		if len(row) != lCols {
+			return nil, 0, fmt.Errorf("R matrix row %d has inconsistent column count", i)
+		}
+	}
+	for i, row := range O {
+		if len(row) != lCols {
+			return nil, 0, fmt.Errorf("O matrix row %d has inconsistent column count", i)
+		}
 	}
 
-	// Build O matrix
-	O, err := buildMatrixFromIntArray(r1csData.O)
-	if err != nil {
-		return matrix.Matrix{}, matrix.Matrix{}, matrix.Matrix{}, fmt.Errorf("failed to build O matrix: %v", err)
+	constraints := make([]Constraint, len(L))
+	for i := range L {
+		constraints[i] = Constraint{
+			L: sparseRow(L[i]),
+			R: sparseRow(R[i]),
+			O: sparseRow(O[i]),
+		}
 	}
 
-	return L, R, O, nil
+	return constraints, lCols, nil
 }
 
-// buildMatrixFromIntArray converts a 2D int array to a matrix.Matrix
-func buildMatrixFromIntArray(data [][]int) (matrix.Matrix, error) {
-	if len(data) == 0 {
-		return matrix.Matrix{}, fmt.Errorf("cannot build matrix from empty data")
+func sparseRow(row []*big.Int) LinearCombination {
+	lc := make(LinearCombination, 0)
+	for j := range row {
+		if row[j].Sign() != 0 {
+			lc = append(lc, Term{Wire: j, Coeff: row[j]})
+		}
 	}
+	return lc
+}
 
-	builder := make(matrix.Builder, len(data))
-	for i, row := range data {
-		builder[i] = make(matrix.Row, len(row))
-		for j, val := range row {
-			builder[i][j] = float64(val)
+// MarshalJSON writes the linear combination as a {"wire": "coeff"} object
+func (lc LinearCombination) MarshalJSON() ([]byte, error) {
+	terms := make(map[string]string, len(lc))
+	for _, t := range lc {
+		terms[strconv.Itoa(t.Wire)] = t.Coeff.String()
+	}
+	return json.Marshal(terms)
+}
+
+func (lc *LinearCombination) UnmarshalJSON(data []byte) error {
+	var terms map[string]json.RawMessage
+	if err := json.Unmarshal(data, &terms); err != nil {
+		return err
+	}
+
+	res := make(LinearCombination, 0, len(terms))
+	for k, raw := range terms {
+		wire, err := strconv.Atoi(k)
+		if err != nil || wire < 0 {
+			return fmt.Errorf("invalid wire index %q", k)
+		}
+		coeff, err := utils.ParseValue(raw)
+		if err != nil {
+			return err
 		}
+		if coeff.Sign() == 0 {
+			continue
+		}
+		res = append(res, Term{Wire: wire, Coeff: coeff})
 	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Wire < res[j].Wire })
 
-	return matrix.Build(builder)
-}
\ No newline at end of file
+	*lc = res
+	return nil
+}
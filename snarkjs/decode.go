@@ -0,0 +1,58 @@
+package snarkjs
+
+import (
+	"fmt"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/keys"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// decoder decodes snarkjs points with the same checks as bls12381.DecodeG1/DecodeG2 and keeps the first error
+type decoder struct {
+	err error
+}
+
+func (d *decoder) g1(field string, p G1, allowIdentity bool) curve.G1Affine {
+	if d.err != nil {
+		return curve.G1Affine{}
+	}
+
+	var point curve.G1Affine
+	var err error
+	switch p[2] {
+	case "0":
+		if !allowIdentity {
+			err = keys.ErrIdentityPoint
+		}
+	case "1":
+		point, err = bls12381.DecodeG1(bls12381.G1AffineJSON{X: p[0], Y: p[1]}, allowIdentity)
+	default:
+		err = fmt.Errorf("%w: expected z = 1, got %q", keys.ErrMalformedCoordinate, p[2])
+	}
+	if err != nil {
+		d.err = &keys.PointError{Field: field, Err: err}
+	}
+	return point
+}
+
+// g2 never accepts the identity, no G2 point of a key or proof can be zero
+func (d *decoder) g2(field string, p G2) curve.G2Affine {
+	if d.err != nil {
+		return curve.G2Affine{}
+	}
+
+	var point curve.G2Affine
+	var err error
+	switch p[2] {
+	case [2]string{"0", "0"}:
+		err = keys.ErrIdentityPoint
+	case [2]string{"1", "0"}:
+		point, err = bls12381.DecodeG2(bls12381.G2AffineJSON{X0: p[0][0], X1: p[0][1], Y0: p[1][0], Y1: p[1][1]}, false)
+	default:
+		err = fmt.Errorf("%w: expected z = [1, 0], got %q", keys.ErrMalformedCoordinate, p[2])
+	}
+	if err != nil {
+		d.err = &keys.PointError{Field: field, Err: err}
+	}
+	return point
+}
@@ -0,0 +1,69 @@
+package snarkjs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-381"
+)
+
+// WriteVerificationKey writes the verifying key as snarkjs's verification_key.json
+func WriteVerificationKey(w io.Writer, vk bls12381.VerifyingKey) error {
+	exported, err := ExportVerifyingKey(vk)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, exported)
+}
+
+// ReadVerificationKey reads snarkjs's verification_key.json
+func ReadVerificationKey(r io.Reader) (bls12381.VerifyingKey, error) {
+	var vk VerificationKey
+	if err := json.NewDecoder(r).Decode(&vk); err != nil {
+		return bls12381.VerifyingKey{}, fmt.Errorf("failed to parse snarkjs verification key: %v", err)
+	}
+	return ImportVerifyingKey(vk)
+}
+
+// WriteProof writes the proof as snarkjs's proof.json
+func WriteProof(w io.Writer, proof bls12381.Proof) error {
+	return writeJSON(w, ExportProof(proof))
+}
+
+// ReadProof reads snarkjs's proof.json
+func ReadProof(r io.Reader) (bls12381.Proof, error) {
+	var proof Proof
+	if err := json.NewDecoder(r).Decode(&proof); err != nil {
+		return bls12381.Proof{}, fmt.Errorf("failed to parse snarkjs proof: %v", err)
+	}
+	return ImportProof(proof)
+}
+
+// WritePublicInputs writes snarkjs's public.json, the public signals without the constant 1
+func WritePublicInputs(w io.Writer, publicInputs []*big.Int) error {
+	signals, err := ExportPublicInputs(publicInputs)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, signals)
+}
+
+// ReadPublicInputs reads snarkjs's public.json and returns our public inputs, the constant 1 included
+func ReadPublicInputs(r io.Reader) ([]*big.Int, error) {
+	var signals []string
+	if err := json.NewDecoder(r).Decode(&signals); err != nil {
+		return nil, fmt.Errorf("failed to parse snarkjs public signals: %v", err)
+	}
+	return ImportPublicInputs(signals)
+}
+
+func writeJSON(w io.Writer, data interface{}) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	_, err = w.Write(jsonData)
+	return err
+}
@@ -0,0 +1,262 @@
+package snarkjs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/keys"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// A .ptau file holds the powers of tau of snarkjs's phase 1 ceremony, e.g. downloaded from a public ceremony.
+// It is an iden3 binfile, see the iden3 package, with the sections:
+//
+//   1: header, n8 (uint32) | q on n8 bytes | power (uint32) | ceremony power (uint32)
+//   2: tauG1, 2^(power+1) - 1 G1 points
+//   3: tauG2, 2^power G2 points
+//   4, 5: alphaTauG1 and betaTauG1, 2^power G1 points each
+//   6: betaG2, a single G2 point
+//   7: the contributions, which are only read by snarkjs, as are the lagrange basis sections of prepared files
+//
+// q is the base field of the curve. Points are affine, each coordinate on n8 bytes in little endian and in
+// Montgomery form (multiplied by 2^(8*n8) mod q), G2 coordinates being written c0 | c1.
+const (
+	ptauHeader     = 1
+	ptauTauG1      = 2
+	ptauTauG2      = 3
+	ptauAlphaTauG1 = 4
+	ptauBetaTauG1  = 5
+	ptauBetaG2     = 6
+)
+
+// montgomeryInv is 2^(-8*fp.Bytes) mod q, which takes a coordinate out of Montgomery form
+var montgomeryInv = func() fp.Element {
+	var r big.Int
+	r.Lsh(big.NewInt(1), 8 * fp.Bytes)
+	r.ModInverse(&r, fp.Modulus())
+
+	var e fp.Element
+	e.SetBigInt(&r)
+	return e
+}()
+
+// ReadPtau reads the first 2^power powers of tau of a BLS12-381 .ptau file as a phase 1 and checks with pairings
+// that they are powers of the same secrets. The rest of the file is skipped, so power should be the smallest
+// that fits the circuit, see PtauPower. The transcript isn't checked, verify the file with snarkjs powersoftau verify.
+func ReadPtau(r io.Reader, power int) (*bls12381.Phase1, error) {
+	if power < 1 || power > bls12381.MaxPower {
+		return nil, fmt.Errorf("the power must be in [1, %d], got %d", bls12381.MaxPower, power)
+	}
+	n := 1 << power
+
+	pr := &ptauReader{r: bufio.NewReader(r)}
+	if !bytes.Equal(pr.read("header", 4), []byte("ptau")) && pr.err == nil {
+		return nil, fmt.Errorf("not a .ptau file, bad magic")
+	}
+	if version := pr.u32("header"); pr.err == nil && version != 1 {
+		return nil, fmt.Errorf("unsupported .ptau version %d", version)
+	}
+
+	p := &bls12381.Phase1{}
+	hasHeader, hasBetaG2 := false, false
+	nbSections := pr.u32("header")
+	for i := uint32(0); i < nbSections && pr.err == nil; i++ {
+		sectionType := pr.u32("section header")
+		size := pr.u64("section header")
+		if pr.err != nil {
+			break
+		}
+		if sectionType != ptauHeader && sectionType <= ptauBetaG2 && !hasHeader {
+			return nil, fmt.Errorf("section %d comes before the header", sectionType)
+		}
+
+		s := &ptauSection{pr: pr, size: size}
+		switch sectionType {
+		case ptauHeader:
+			if err := s.header(power); err != nil {
+				return nil, err
+			}
+			hasHeader = true
+		case ptauTauG1:
+			p.TauG1 = s.g1s("tauG1", 2*n - 1)
+		case ptauTauG2:
+			p.TauG2 = s.g2s("tauG2", n)
+		case ptauAlphaTauG1:
+			p.AlphaTauG1 = s.g1s("alphaTauG1", n)
+		case ptauBetaTauG1:
+			p.BetaTauG1 = s.g1s("betaTauG1", n)
+		case ptauBetaG2:
+			betaG2 := s.g2s("betaG2", 1)
+			if pr.err == nil {
+				p.BetaG2, hasBetaG2 = betaG2[0], true
+			}
+		}
+		s.skipRest()
+	}
+	if pr.err != nil {
+		return nil, pr.err
+	}
+	if !hasHeader || p.TauG1 == nil || p.TauG2 == nil || p.AlphaTauG1 == nil || p.BetaTauG1 == nil || !hasBetaG2 {
+		return nil, fmt.Errorf("the .ptau file is missing sections, expected the header, tauG1, tauG2, alphaTauG1, betaTauG1 and betaG2")
+	}
+
+	if err := p.VerifyPowers(); err != nil {
+		return nil, fmt.Errorf("inconsistent .ptau file: %v", err)
+	}
+	return p, nil
+}
+
+// PtauPower returns the smallest power of a .ptau file that fits an R1CS of nbConstraints constraints
+func PtauPower(nbConstraints int) int {
+	domain := bls12381.NewDomain(nbConstraints)
+	return max(bits.TrailingZeros64(domain.Cardinality), 1)
+}
+
+// ptauReader reads little endian values and keeps the first error
+type ptauReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (pr *ptauReader) read(field string, n int) []byte {
+	b := make([]byte, n)
+	if pr.err != nil {
+		return b
+	}
+	if _, err := io.ReadFull(pr.r, b); err != nil {
+		pr.err = fmt.Errorf("%s: %v", field, err)
+	}
+	return b
+}
+
+func (pr *ptauReader) u32(field string) uint32 {
+	return binary.LittleEndian.Uint32(pr.read(field, 4))
+}
+
+func (pr *ptauReader) u64(field string) uint64 {
+	return binary.LittleEndian.Uint64(pr.read(field, 8))
+}
+
+// ptauSection reads the beginning of a section, skipRest then discards the points past the ones needed
+type ptauSection struct {
+	pr   *ptauReader
+	size uint64
+	read uint64
+}
+
+func (s *ptauSection) bytes(field string, n int) []byte {
+	if s.pr.err == nil && s.read + uint64(n) > s.size {
+		s.pr.err = fmt.Errorf("%s: the section is too short", field)
+	}
+	s.read += uint64(n)
+	return s.pr.read(field, n)
+}
+
+func (s *ptauSection) skipRest() {
+	if s.pr.err != nil || s.read >= s.size {
+		return
+	}
+	if _, err := s.pr.r.Discard(int(s.size - s.read)); err != nil {
+		s.pr.err = fmt.Errorf("failed to skip a section: %v", err)
+	}
+}
+
+// header checks that the file is made on BLS12-381 and holds at least 2^power powers
+func (s *ptauSection) header(power int) error {
+	n8 := binary.LittleEndian.Uint32(s.bytes("header", 4))
+	if s.pr.err != nil {
+		return s.pr.err
+	}
+	if n8 != fp.Bytes {
+		return fmt.Errorf("only BLS12-381 .ptau files are supported, the file's field elements are %d bytes long", n8)
+	}
+	q := s.bytes("header", fp.Bytes)
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	if s.pr.err == nil && new(big.Int).SetBytes(q).Cmp(fp.Modulus()) != 0 {
+		return fmt.Errorf("only BLS12-381 .ptau files are supported, the file was made on another curve")
+	}
+	filePower := binary.LittleEndian.Uint32(s.bytes("header", 4))
+	if s.pr.err == nil && int(filePower) < power {
+		return fmt.Errorf("the .ptau file holds 2^%d powers of tau, 2^%d are needed", filePower, power)
+	}
+	return s.pr.err
+}
+
+// coordinate reads a coordinate out of Montgomery form, a read error being kept by the reader
+func (s *ptauSection) coordinate(field string) (fp.Element, error) {
+	var b [fp.Bytes]byte
+	copy(b[:], s.bytes(field, fp.Bytes))
+	if s.pr.err != nil {
+		return fp.Element{}, nil
+	}
+	e, err := fp.LittleEndian.Element(&b)
+	if err != nil {
+		return fp.Element{}, fmt.Errorf("%w: %v", keys.ErrMalformedCoordinate, err)
+	}
+	return *e.Mul(&e, &montgomeryInv), nil
+}
+
+// coordinates reads the coordinates of a point, the error being reported on the point
+func (s *ptauSection) coordinates(field string, i int, coordinates ...*fp.Element) error {
+	for _, c := range coordinates {
+		var err error
+		if *c, err = s.coordinate(field); err != nil {
+			return &keys.PointError{Field: fmt.Sprintf("%s[%d]", field, i), Err: err}
+		}
+	}
+	return nil
+}
+
+// g1s reads the first n points of the section and checks that they are on the curve and in the subgroup
+func (s *ptauSection) g1s(field string, n int) []curve.G1Affine {
+	points := make([]curve.G1Affine, n)
+	for i := 0; i < n && s.pr.err == nil; i++ {
+		err := s.coordinates(field, i, &points[i].X, &points[i].Y)
+		if err == nil && s.pr.err == nil {
+			err = checkPoint(field, i, points[i].IsInfinity(), points[i].IsOnCurve(), points[i].IsInSubGroup)
+		}
+		if err != nil {
+			s.pr.err = err
+		}
+	}
+	return points
+}
+
+// g2s is g1s for G2 points
+func (s *ptauSection) g2s(field string, n int) []curve.G2Affine {
+	points := make([]curve.G2Affine, n)
+	for i := 0; i < n && s.pr.err == nil; i++ {
+		err := s.coordinates(field, i, &points[i].X.A0, &points[i].X.A1, &points[i].Y.A0, &points[i].Y.A1)
+		if err == nil && s.pr.err == nil {
+			err = checkPoint(field, i, points[i].IsInfinity(), points[i].IsOnCurve(), points[i].IsInSubGroup)
+		}
+		if err != nil {
+			s.pr.err = err
+		}
+	}
+	return points
+}
+
+// checkPoint rejects the identity and points off the curve or out of the subgroup, no power of tau can be any of them
+func checkPoint(field string, i int, isInfinity, isOnCurve bool, isInSubGroup func() bool) error {
+	var err error
+	switch {
+	case isInfinity:
+		err = keys.ErrIdentityPoint
+	case !isOnCurve:
+		err = keys.ErrNotOnCurve
+	case !isInSubGroup():
+		err = keys.ErrNotInSubgroup
+	default:
+		return nil
+	}
+	return &keys.PointError{Field: fmt.Sprintf("%s[%d]", field, i), Err: err}
+}
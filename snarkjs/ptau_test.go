@@ -0,0 +1,103 @@
+package snarkjs_test
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"r1cs-zk-go/snarkjs"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// testdata/powers_of_7.ptau was written by a .ptau encoder independent from this package. It holds 2^2 powers
+// of tau = 7 with alpha = 11 and beta = 13, coordinates in Montgomery form, followed by a contributions section
+// that ReadPtau must skip.
+const (
+	ptauTau   = 7
+	ptauAlpha = 11
+	ptauBeta  = 13
+	// offsets in the file of the prime in the header section and of the points of the tauG1 section
+	ptauPrimeOffset = 28
+	ptauTauG1Offset = 96
+)
+
+// TestReadPtau checks that the points read are the known multiples of the generators, for the whole file and
+// for the first powers only
+func TestReadPtau(t *testing.T) {
+	file := readPtauFixture(t)
+	for _, power := range []int{1, 2} {
+		p, err := snarkjs.ReadPtau(bytes.NewReader(file), power)
+		if err != nil {
+			t.Fatalf("power %d: %v", power, err)
+		}
+		n := 1 << power
+		if len(p.TauG1) != 2*n - 1 || len(p.TauG2) != n || len(p.AlphaTauG1) != n || len(p.BetaTauG1) != n {
+			t.Fatalf("power %d: expected %d, %d, %d and %d points, got %d, %d, %d and %d", power, 2*n - 1, n, n, n,
+				len(p.TauG1), len(p.TauG2), len(p.AlphaTauG1), len(p.BetaTauG1))
+		}
+
+		tauI := big.NewInt(1)
+		for i := range p.TauG1 {
+			checkG1(t, "tauG1", i, p.TauG1[i], tauI)
+			if i < n {
+				checkG2(t, "tauG2", i, p.TauG2[i], tauI)
+				checkG1(t, "alphaTauG1", i, p.AlphaTauG1[i], new(big.Int).Mul(tauI, big.NewInt(ptauAlpha)))
+				checkG1(t, "betaTauG1", i, p.BetaTauG1[i], new(big.Int).Mul(tauI, big.NewInt(ptauBeta)))
+			}
+			tauI.Mul(tauI, big.NewInt(ptauTau))
+		}
+		checkG2(t, "betaG2", 0, p.BetaG2, big.NewInt(ptauBeta))
+	}
+}
+
+// TestReadPtauErrors checks that files too small, of another format or curve, truncated or holding points that
+// aren't powers of the same secrets are rejected
+func TestReadPtauErrors(t *testing.T) {
+	file := readPtauFixture(t)
+	if _, err := snarkjs.ReadPtau(bytes.NewReader(file), 3); err == nil {
+		t.Error("read 2^3 powers from a file of 2^2")
+	}
+
+	for name, tamper := range map[string]func(file []byte) []byte{
+		"magic":     func(file []byte) []byte { return append([]byte("ptay"), file[4:]...) },
+		"prime":     func(file []byte) []byte { file[ptauPrimeOffset]++; return file },
+		"truncated": func(file []byte) []byte { return file[:len(file) / 2] },
+		"swapped powers": func(file []byte) []byte {
+			first, second := file[ptauTauG1Offset + 96:ptauTauG1Offset + 192], file[ptauTauG1Offset + 192:ptauTauG1Offset + 288]
+			swapped := append(append([]byte(nil), second...), first...)
+			copy(file[ptauTauG1Offset + 96:], swapped)
+			return file
+		},
+		"point off the curve": func(file []byte) []byte { file[ptauTauG1Offset + 96]++; return file },
+	} {
+		tampered := tamper(append([]byte(nil), file...))
+		if _, err := snarkjs.ReadPtau(bytes.NewReader(tampered), 2); err == nil {
+			t.Errorf("%s: the tampered file was read", name)
+		}
+	}
+}
+
+func readPtauFixture(t *testing.T) []byte {
+	file, err := os.ReadFile("testdata/powers_of_7.ptau")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func checkG1(t *testing.T, field string, i int, p curve.G1Affine, s *big.Int) {
+	var expected curve.G1Affine
+	expected.ScalarMultiplicationBase(s)
+	if !p.Equal(&expected) {
+		t.Fatalf("%s[%d] isn't [%s]_1", field, i, s)
+	}
+}
+
+func checkG2(t *testing.T, field string, i int, p curve.G2Affine, s *big.Int) {
+	var expected curve.G2Affine
+	expected.ScalarMultiplicationBase(s)
+	if !p.Equal(&expected) {
+		t.Fatalf("%s[%d] isn't [%s]_2", field, i, s)
+	}
+}
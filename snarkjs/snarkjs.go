@@ -0,0 +1,227 @@
+// Package snarkjs converts verifying keys, proofs and public inputs to and from the JSON files of snarkjs's
+// groth16 prover on BLS12-381 (verification_key.json, proof.json and public.json). Only keys and proofs made
+// on BLS12-381, see groth16/bls12-381, can be converted. The powers of tau of a snarkjs .ptau file can be read
+// as the phase 1 of a ceremony, see ReadPtau.
+//
+// snarkjs checks e(-A, B) * e(alpha, beta) * e(vk_x, gamma) * e(C, delta) = 1 with vk_x = IC[0] + sum_i pub_i*IC[i+1],
+// which is this repo's verification equation: IC is VerifierPsi and the constant 1 at the start of our public
+// inputs is left out of snarkjs's public.json.
+package snarkjs
+
+import (
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/utils"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	protocol  = "groth16"
+	curveName = "bls12381"
+)
+
+// VerificationKey is the layout of snarkjs's verification_key.json
+type VerificationKey struct {
+	Protocol      string `json:"protocol"`
+	Curve         string `json:"curve"`
+	NPublic       int    `json:"nPublic"`
+	VkAlpha1      G1     `json:"vk_alpha_1"`
+	VkBeta2       G2     `json:"vk_beta_2"`
+	VkGamma2      G2     `json:"vk_gamma_2"`
+	VkDelta2      G2     `json:"vk_delta_2"`
+	VkAlphabeta12 *GT    `json:"vk_alphabeta_12,omitempty"`
+	IC            []G1   `json:"IC"`
+}
+
+// Proof is the layout of snarkjs's proof.json
+type Proof struct {
+	PiA      G1     `json:"pi_a"`
+	PiB      G2     `json:"pi_b"`
+	PiC      G1     `json:"pi_c"`
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+}
+
+// G1 is a point in projective coordinates [x, y, z], snarkjs writes z = 1 or [0, 1, 0] for the identity
+type G1 [3]string
+
+// G2 is [[x0, x1], [y0, y1], [z0, z1]], see G1
+type G2 [3][2]string
+
+// GT is [c0, c1] with c_i = [b0, b1, b2] and b_j = [a0, a1]
+type GT [2][3][2]string
+
+// ExportVerifyingKey converts the verifying key, e(alpha, beta) is computed when the key doesn't carry it
+func ExportVerifyingKey(vk bls12381.VerifyingKey) (VerificationKey, error) {
+	if len(vk.VerifierPsi) == 0 {
+		return VerificationKey{}, fmt.Errorf("snarkjs expects the constant 1 as first public input, the verifying key has no public input")
+	}
+
+	alphaBeta := vk.AlphaBeta
+	if alphaBeta == nil {
+		e, err := curve.Pair([]curve.G1Affine{vk.Alpha}, []curve.G2Affine{vk.Beta})
+		if err != nil {
+			return VerificationKey{}, fmt.Errorf("failed to compute e(alpha, beta): %v", err)
+		}
+		alphaBeta = &e
+	}
+	gt := gtToSnarkjs(alphaBeta)
+
+	ic := make([]G1, len(vk.VerifierPsi))
+	for i := range vk.VerifierPsi {
+		ic[i] = g1ToSnarkjs(&vk.VerifierPsi[i])
+	}
+
+	return VerificationKey{
+		Protocol:      protocol,
+		Curve:         curveName,
+		NPublic:       len(ic) - 1,
+		VkAlpha1:      g1ToSnarkjs(&vk.Alpha),
+		VkBeta2:       g2ToSnarkjs(&vk.Beta),
+		VkGamma2:      g2ToSnarkjs(&vk.Gamma),
+		VkDelta2:      g2ToSnarkjs(&vk.Teta),
+		VkAlphabeta12: &gt,
+		IC:            ic,
+	}, nil
+}
+
+// ImportVerifyingKey converts and strictly decodes a snarkjs verification key
+func ImportVerifyingKey(vk VerificationKey) (bls12381.VerifyingKey, error) {
+	if err := checkHeader(vk.Protocol, vk.Curve); err != nil {
+		return bls12381.VerifyingKey{}, err
+	}
+	if len(vk.IC) != vk.NPublic + 1 {
+		return bls12381.VerifyingKey{}, fmt.Errorf("IC must have nPublic + 1 = %d points, got %d", vk.NPublic + 1, len(vk.IC))
+	}
+
+	var d decoder
+	decoded := bls12381.VerifyingKey{
+		Alpha:       d.g1("vk_alpha_1", vk.VkAlpha1, false),
+		Beta:        d.g2("vk_beta_2", vk.VkBeta2),
+		Gamma:       d.g2("vk_gamma_2", vk.VkGamma2),
+		Teta:        d.g2("vk_delta_2", vk.VkDelta2),
+		VerifierPsi: make([]curve.G1Affine, len(vk.IC)),
+	}
+	for i := range vk.IC {
+		decoded.VerifierPsi[i] = d.g1(fmt.Sprintf("IC[%d]", i), vk.IC[i], true)
+	}
+	if d.err != nil {
+		return bls12381.VerifyingKey{}, d.err
+	}
+
+	// vk_alphabeta_12 is informative, snarkjs never uses it to verify and its pairing may not
+	// normalize GT elements like gnark-crypto, so e(alpha, beta) is recomputed
+	alphaBeta, err := curve.Pair([]curve.G1Affine{decoded.Alpha}, []curve.G2Affine{decoded.Beta})
+	if err != nil {
+		return bls12381.VerifyingKey{}, fmt.Errorf("failed to compute e(alpha, beta): %v", err)
+	}
+	decoded.AlphaBeta = &alphaBeta
+
+	return decoded, nil
+}
+
+// ExportProof converts the proof
+func ExportProof(proof bls12381.Proof) Proof {
+	return Proof{
+		PiA:      g1ToSnarkjs(&proof.A),
+		PiB:      g2ToSnarkjs(&proof.B),
+		PiC:      g1ToSnarkjs(&proof.C),
+		Protocol: protocol,
+		Curve:    curveName,
+	}
+}
+
+// ImportProof converts and strictly decodes a snarkjs proof. Invalid points are returned as a *keys.PointError.
+func ImportProof(proof Proof) (bls12381.Proof, error) {
+	if err := checkHeader(proof.Protocol, proof.Curve); err != nil {
+		return bls12381.Proof{}, err
+	}
+
+	var d decoder
+	decoded := bls12381.Proof{
+		A: d.g1("pi_a", proof.PiA, false),
+		B: d.g2("pi_b", proof.PiB),
+		C: d.g1("pi_c", proof.PiC, false),
+	}
+	if d.err != nil {
+		return bls12381.Proof{}, d.err
+	}
+
+	return decoded, nil
+}
+
+// ExportPublicInputs drops the constant 1 that starts our public inputs, snarkjs adds IC[0] on its own.
+// The signals are reduced modulo r.
+func ExportPublicInputs(publicInputs []*big.Int) ([]string, error) {
+	reduced := utils.Reduce(publicInputs, fr.Modulus())
+	if len(reduced) == 0 || reduced[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("snarkjs expects the constant 1 as first public input")
+	}
+
+	signals := make([]string, len(reduced) - 1)
+	for i := range signals {
+		signals[i] = reduced[i+1].String()
+	}
+	return signals, nil
+}
+
+// ImportPublicInputs parses snarkjs's decimal public signals and puts the constant 1 back in front
+func ImportPublicInputs(signals []string) ([]*big.Int, error) {
+	publicInputs := make([]*big.Int, len(signals) + 1)
+	publicInputs[0] = big.NewInt(1)
+	for i, s := range signals {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok || v.Sign() < 0 || v.Cmp(fr.Modulus()) >= 0 {
+			return nil, fmt.Errorf("public signal %d is not a decimal integer in [0, r): %q", i, s)
+		}
+		publicInputs[i+1] = v
+	}
+	return publicInputs, nil
+}
+
+func checkHeader(p, c string) error {
+	if p != protocol {
+		return fmt.Errorf("expected protocol %q, got %q", protocol, p)
+	}
+	if c != curveName {
+		return fmt.Errorf("expected curve %q, got %q", curveName, c)
+	}
+	return nil
+}
+
+func g1ToSnarkjs(p *curve.G1Affine) G1 {
+	if p.IsInfinity() {
+		return G1{"0", "1", "0"}
+	}
+	return G1{fpToString(&p.X), fpToString(&p.Y), "1"}
+}
+
+func g2ToSnarkjs(p *curve.G2Affine) G2 {
+	if p.IsInfinity() {
+		return G2{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return G2{
+		{fpToString(&p.X.A0), fpToString(&p.X.A1)},
+		{fpToString(&p.Y.A0), fpToString(&p.Y.A1)},
+		{"1", "0"},
+	}
+}
+
+func gtToSnarkjs(e *curve.GT) GT {
+	return GT{
+		{fp2ToSnarkjs(&e.C0.B0.A0, &e.C0.B0.A1), fp2ToSnarkjs(&e.C0.B1.A0, &e.C0.B1.A1), fp2ToSnarkjs(&e.C0.B2.A0, &e.C0.B2.A1)},
+		{fp2ToSnarkjs(&e.C1.B0.A0, &e.C1.B0.A1), fp2ToSnarkjs(&e.C1.B1.A0, &e.C1.B1.A1), fp2ToSnarkjs(&e.C1.B2.A0, &e.C1.B2.A1)},
+	}
+}
+
+func fp2ToSnarkjs(a0, a1 *fp.Element) [2]string {
+	return [2]string{fpToString(a0), fpToString(a1)}
+}
+
+func fpToString(e *fp.Element) string {
+	var b big.Int
+	return e.BigInt(&b).String()
+}
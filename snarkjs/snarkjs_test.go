@@ -0,0 +1,214 @@
+package snarkjs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/r1cs"
+	"r1cs-zk-go/snarkjs"
+	"r1cs-zk-go/witness"
+	"strings"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// the x^3 + 5x + 5 = 155 example of the README
+const (
+	exampleR1CS = `{
+		"nbPublicInputs": 2,
+		"L": [[0, 0, 0, 1], [0, 0, 0, 1]],
+		"R": [[0, 0, 0, 1], [0, 0, 1, 0]],
+		"O": [[0, 0, 1, 0], [-5, 1, 0, -5]]
+	}`
+	exampleWitness = `{"publicInputs": [1, 155], "privateInputs": [25, 5]}`
+)
+
+// TestRoundTrip exports a verifying key and a proof, checks them with snarkjs's verification equation computed
+// from the JSON files alone, and imports them back
+func TestRoundTrip(t *testing.T) {
+	vk, proof, publicInputs := proveExample(t)
+
+	var vkFile, proofFile, publicFile bytes.Buffer
+	if err := snarkjs.WriteVerificationKey(&vkFile, vk); err != nil {
+		t.Fatal(err)
+	}
+	if err := snarkjs.WriteProof(&proofFile, proof); err != nil {
+		t.Fatal(err)
+	}
+	if err := snarkjs.WritePublicInputs(&publicFile, publicInputs); err != nil {
+		t.Fatal(err)
+	}
+
+	var signals []string
+	if err := json.Unmarshal(publicFile.Bytes(), &signals); err != nil {
+		t.Fatal(err)
+	}
+	if !snarkjsVerify(t, vkFile.Bytes(), proofFile.Bytes(), signals) {
+		t.Fatal("snarkjs's equation doesn't hold for the exported files")
+	}
+	if snarkjsVerify(t, vkFile.Bytes(), proofFile.Bytes(), []string{"154"}) {
+		t.Fatal("snarkjs's equation holds for other public signals")
+	}
+
+	importedVk, err := snarkjs.ReadVerificationKey(bytes.NewReader(vkFile.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	importedProof, err := snarkjs.ReadProof(bytes.NewReader(proofFile.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	importedPublicInputs, err := snarkjs.ReadPublicInputs(bytes.NewReader(publicFile.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !importedVk.Alpha.Equal(&vk.Alpha) || !importedVk.Beta.Equal(&vk.Beta) || !importedVk.Gamma.Equal(&vk.Gamma) ||
+		!importedVk.Teta.Equal(&vk.Teta) || len(importedVk.VerifierPsi) != len(vk.VerifierPsi) {
+		t.Fatal("the imported verifying key differs from the exported one")
+	}
+	for i := range vk.VerifierPsi {
+		if !importedVk.VerifierPsi[i].Equal(&vk.VerifierPsi[i]) {
+			t.Fatalf("the imported IC[%d] differs from the exported one", i)
+		}
+	}
+	if importedVk.AlphaBeta == nil || !importedVk.AlphaBeta.Equal(vk.AlphaBeta) {
+		t.Fatal("the imported e(alpha, beta) differs from the exported one")
+	}
+	if !importedProof.A.Equal(&proof.A) || !importedProof.B.Equal(&proof.B) || !importedProof.C.Equal(&proof.C) {
+		t.Fatal("the imported proof differs from the exported one")
+	}
+	if len(importedPublicInputs) != len(publicInputs) {
+		t.Fatalf("expected %d public inputs, got %d", len(publicInputs), len(importedPublicInputs))
+	}
+	for i := range publicInputs {
+		if importedPublicInputs[i].Cmp(publicInputs[i]) != 0 {
+			t.Fatalf("public input %d: expected %s, got %s", i, publicInputs[i], importedPublicInputs[i])
+		}
+	}
+
+	ok, err := bls12381.Verify(importedVk, importedProof, bls12381.Elements(importedPublicInputs))
+	if err != nil || !ok {
+		t.Fatalf("the imported proof doesn't verify: %v", err)
+	}
+}
+
+func proveExample(t *testing.T) (bls12381.VerifyingKey, bls12381.Proof, []*big.Int) {
+	r1csData, err := r1cs.ReadR1CS(strings.NewReader(exampleR1CS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessData, err := witness.ReadWitness(strings.NewReader(exampleWitness))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, vk, err := bls12381.Setup(r1csData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := bls12381.Prove(pk, r1csData, witnessData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof, witnessData.PublicInputs
+}
+
+// snarkjsVerify checks e(-A, B) * e(alpha, beta) * e(vk_x, gamma) * e(C, delta) = 1 with
+// vk_x = IC[0] + sum_i pub_i * IC[i+1], as snarkjs groth16 verify does, reading the points from the JSON files
+func snarkjsVerify(t *testing.T, vkFile, proofFile []byte, signals []string) bool {
+	var vk struct {
+		Alpha [3]string    `json:"vk_alpha_1"`
+		Beta  [3][2]string `json:"vk_beta_2"`
+		Gamma [3][2]string `json:"vk_gamma_2"`
+		Delta [3][2]string `json:"vk_delta_2"`
+		IC    [][3]string  `json:"IC"`
+	}
+	var proof struct {
+		A [3]string    `json:"pi_a"`
+		B [3][2]string `json:"pi_b"`
+		C [3]string    `json:"pi_c"`
+	}
+	if err := json.Unmarshal(vkFile, &vk); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(proofFile, &proof); err != nil {
+		t.Fatal(err)
+	}
+	if len(vk.IC) != len(signals) + 1 {
+		t.Fatalf("expected %d IC points, got %d", len(signals) + 1, len(vk.IC))
+	}
+
+	vkX := parseG1(t, vk.IC[0])
+	for i, s := range signals {
+		var pub fr.Element
+		if _, err := pub.SetString(s); err != nil {
+			t.Fatal(err)
+		}
+		ic := parseG1(t, vk.IC[i+1])
+		var term curve.G1Affine
+		term.ScalarMultiplication(&ic, pub.BigInt(new(big.Int)))
+		vkX.Add(&vkX, &term)
+	}
+
+	var negA curve.G1Affine
+	a := parseG1(t, proof.A)
+	negA.Neg(&a)
+	ok, err := curve.PairingCheck(
+		[]curve.G1Affine{negA, parseG1(t, vk.Alpha), vkX, parseG1(t, proof.C)},
+		[]curve.G2Affine{parseG2(t, proof.B), parseG2(t, vk.Beta), parseG2(t, vk.Gamma), parseG2(t, vk.Delta)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+// parseG1 converts snarkjs's projective [x, y, z] to affine coordinates
+func parseG1(t *testing.T, coordinates [3]string) curve.G1Affine {
+	x, y, z := parseFp(t, coordinates[0]), parseFp(t, coordinates[1]), parseFp(t, coordinates[2])
+	var p curve.G1Affine
+	if z.IsZero() {
+		return p
+	}
+	z.Inverse(&z)
+	p.X.Mul(&x, &z)
+	p.Y.Mul(&y, &z)
+	if !p.IsOnCurve() {
+		t.Fatalf("%v is not on the curve", coordinates)
+	}
+	return p
+}
+
+// parseG2 is parseG1 for [[x0, x1], [y0, y1], [z0, z1]]
+func parseG2(t *testing.T, coordinates [3][2]string) curve.G2Affine {
+	var x, y, z curve.E2
+	x.A0, x.A1 = parseFp(t, coordinates[0][0]), parseFp(t, coordinates[0][1])
+	y.A0, y.A1 = parseFp(t, coordinates[1][0]), parseFp(t, coordinates[1][1])
+	z.A0, z.A1 = parseFp(t, coordinates[2][0]), parseFp(t, coordinates[2][1])
+	var p curve.G2Affine
+	if z.IsZero() {
+		return p
+	}
+	z.Inverse(&z)
+	p.X.Mul(&x, &z)
+	p.Y.Mul(&y, &z)
+	if !p.IsOnCurve() {
+		t.Fatalf("%v is not on the curve", coordinates)
+	}
+	return p
+}
+
+func parseFp(t *testing.T, s string) fp.Element {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Cmp(fp.Modulus()) >= 0 {
+		t.Fatalf("%q is not a coordinate", s)
+	}
+	var e fp.Element
+	e.SetBigInt(v)
+	return e
+}
@@ -0,0 +1,104 @@
+// Package solidity generates a Solidity Groth16 verifier for a verifying key, checked on chain with the
+// EIP-2537 BLS12-381 precompiles, and encodes proofs as calldata for it. Only keys and proofs made on
+// BLS12-381, see groth16/bls12-381, have precompiles.
+//
+// EIP-2537 encodes an Fp element on 64 bytes, 16 zero bytes followed by the 48 bytes big endian value,
+// a G1 point as x|y (128 bytes), a G2 point as x.A0|x.A1|y.A0|y.A1 (256 bytes) and the identity as zeros.
+package solidity
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/utils"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	SizeOfFp = 64
+	SizeOfG1 = 2 * SizeOfFp
+	SizeOfG2 = 4 * SizeOfFp
+	// SizeOfProof is the size of the proof argument of verifyProof: A|B|C
+	SizeOfProof = 2 * SizeOfG1 + SizeOfG2
+)
+
+// verifyProofSelector is the first 4 bytes of keccak256("verifyProof(bytes,uint256[])")
+var verifyProofSelector = []byte{0x1e, 0x8e, 0x1e, 0x13}
+
+// EncodeG1 returns the EIP-2537 encoding of a G1 point
+func EncodeG1(p *curve.G1Affine) []byte {
+	res := make([]byte, SizeOfG1)
+	if p.IsInfinity() {
+		return res
+	}
+	putFp(res[0:], &p.X)
+	putFp(res[SizeOfFp:], &p.Y)
+	return res
+}
+
+// EncodeG2 returns the EIP-2537 encoding of a G2 point
+func EncodeG2(p *curve.G2Affine) []byte {
+	res := make([]byte, SizeOfG2)
+	if p.IsInfinity() {
+		return res
+	}
+	putFp(res[0:], &p.X.A0)
+	putFp(res[SizeOfFp:], &p.X.A1)
+	putFp(res[2*SizeOfFp:], &p.Y.A0)
+	putFp(res[3*SizeOfFp:], &p.Y.A1)
+	return res
+}
+
+func putFp(b []byte, e *fp.Element) {
+	v := e.Bytes()
+	copy(b[SizeOfFp-fp.Bytes:SizeOfFp], v[:])
+}
+
+// EncodeProof returns the proof argument of verifyProof, A|B|C
+func EncodeProof(proof bls12381.Proof) []byte {
+	res := make([]byte, 0, SizeOfProof)
+	res = append(res, EncodeG1(&proof.A)...)
+	res = append(res, EncodeG2(&proof.B)...)
+	return append(res, EncodeG1(&proof.C)...)
+}
+
+// EncodePublicInputs returns the input argument of verifyProof. Like snarkjs, the contract adds the
+// constant 1 that starts our public inputs on its own. The inputs are reduced modulo r.
+func EncodePublicInputs(publicInputs []*big.Int) ([]*big.Int, error) {
+	reduced := utils.Reduce(publicInputs, fr.Modulus())
+	if len(reduced) == 0 || reduced[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("the verifier contract expects the constant 1 as first public input")
+	}
+	return reduced[1:], nil
+}
+
+// Calldata returns the ABI encoded call to verifyProof(bytes proof, uint256[] input)
+func Calldata(proof bls12381.Proof, publicInputs []*big.Int) ([]byte, error) {
+	inputs, err := EncodePublicInputs(publicInputs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]byte{}, verifyProofSelector...)
+	// head: the offsets of the two dynamic arguments
+	res = append(res, word(2 * 32)...)
+	res = append(res, word(2 * 32 + 32 + SizeOfProof)...)
+	// tail: the proof, whose size is a multiple of 32, then the inputs
+	res = append(res, word(SizeOfProof)...)
+	res = append(res, EncodeProof(proof)...)
+	res = append(res, word(uint64(len(inputs)))...)
+	for _, input := range inputs {
+		res = append(res, input.FillBytes(make([]byte, 32))...)
+	}
+
+	return res, nil
+}
+
+func word(v uint64) []byte {
+	b := make([]byte, 32)
+	binary.BigEndian.PutUint64(b[24:], v)
+	return b
+}
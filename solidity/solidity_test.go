@@ -0,0 +1,91 @@
+package solidity_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"r1cs-zk-go/groth16/bls12-381"
+	"r1cs-zk-go/solidity"
+	"strings"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// go test ./solidity -update rewrites the golden files after an intended change of the contract or the calldata
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestWriteVerifierGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := solidity.WriteVerifier(&buf, fixedVerifyingKey()); err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "testdata/Verifier.sol.golden", buf.Bytes())
+}
+
+func TestCalldataGolden(t *testing.T) {
+	calldata, err := solidity.Calldata(fixedProof(), []*big.Int{big.NewInt(1), big.NewInt(155)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "testdata/calldata.golden", []byte(hex.EncodeToString(calldata) + "\n"))
+}
+
+func compareGolden(t *testing.T, path string, got []byte) {
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, golden) {
+		t.Errorf("the output differs from %s, run go test ./solidity -update if the change is intended\n%s", path, firstDifference(got, golden))
+	}
+}
+
+// firstDifference returns the first line that differs, the whole contract is too long to print
+func firstDifference(got, golden []byte) string {
+	gotLines, goldenLines := strings.Split(string(got), "\n"), strings.Split(string(golden), "\n")
+	for i := 0; i < len(gotLines) && i < len(goldenLines); i++ {
+		if gotLines[i] != goldenLines[i] {
+			return fmt.Sprintf("line %d:\ngot  %s\nwant %s", i + 1, gotLines[i], goldenLines[i])
+		}
+	}
+	return "the lengths differ"
+}
+
+// fixedVerifyingKey returns a verifying key for 2 public inputs made of multiples of the generators, the
+// contract only depends on the points so they don't need to come from a setup
+func fixedVerifyingKey() bls12381.VerifyingKey {
+	return bls12381.VerifyingKey{
+		Alpha:       g1(2),
+		Beta:        g2(3),
+		Gamma:       g2(5),
+		Teta:        g2(7),
+		VerifierPsi: []curve.G1Affine{g1(11), g1(13)},
+	}
+}
+
+func fixedProof() bls12381.Proof {
+	return bls12381.Proof{A: g1(17), B: g2(19), C: g1(23)}
+}
+
+func g1(k int64) curve.G1Affine {
+	_, _, g, _ := curve.Generators()
+	var p curve.G1Affine
+	p.ScalarMultiplication(&g, big.NewInt(k))
+	return p
+}
+
+func g2(k int64) curve.G2Affine {
+	_, _, _, g := curve.Generators()
+	var p curve.G2Affine
+	p.ScalarMultiplication(&g, big.NewInt(k))
+	return p
+}
@@ -0,0 +1,108 @@
+package solidity
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"text/template"
+	"r1cs-zk-go/groth16/bls12-381"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// WriteVerifier writes a self-contained Solidity contract verifying proofs for the verifying key.
+// beta, gamma and delta are negated at generation time so the contract never negates a point:
+// e(A, B) * e(alpha, -beta) * e(vk_x, -gamma) * e(C, -delta) = 1.
+func WriteVerifier(w io.Writer, vk bls12381.VerifyingKey) error {
+	if len(vk.VerifierPsi) == 0 {
+		return fmt.Errorf("the verifier contract expects the constant 1 as first public input, the verifying key has no public input")
+	}
+
+	var negBeta, negGamma, negDelta curve.G2Affine
+	negBeta.Neg(&vk.Beta)
+	negGamma.Neg(&vk.Gamma)
+	negDelta.Neg(&vk.Teta)
+
+	ic := make([]byte, 0, len(vk.VerifierPsi) * SizeOfG1)
+	for i := range vk.VerifierPsi {
+		ic = append(ic, EncodeG1(&vk.VerifierPsi[i])...)
+	}
+
+	return verifierTemplate.Execute(w, struct {
+		NbPublicInputs                        int
+		R, Alpha, NegBeta, NegGamma, NegDelta string
+		IC                                    string
+	}{
+		NbPublicInputs: len(vk.VerifierPsi) - 1,
+		R:              fr.Modulus().Text(16),
+		Alpha:          hex.EncodeToString(EncodeG1(&vk.Alpha)),
+		NegBeta:        hex.EncodeToString(EncodeG2(&negBeta)),
+		NegGamma:       hex.EncodeToString(EncodeG2(&negGamma)),
+		NegDelta:       hex.EncodeToString(EncodeG2(&negDelta)),
+		IC:             hex.EncodeToString(ic),
+	})
+}
+
+var verifierTemplate = template.Must(template.New("verifier").Parse(`// SPDX-License-Identifier: MIT
+// Code generated by r1cs-zk-go export-verifier. DO NOT EDIT.
+pragma solidity ^0.8.24;
+
+/// @title Groth16 verifier over BLS12-381
+/// @notice Checks e(A, B) * e(alpha, -beta) * e(vk_x, -gamma) * e(C, -delta) = 1 where
+/// vk_x = IC[0] + sum_i input[i] * IC[i + 1], with the EIP-2537 G1MSM and PAIRING_CHECK precompiles.
+contract Groth16Verifier {
+    address constant G1_MSM = address(0x0c);
+    address constant PAIRING_CHECK = address(0x0f);
+
+    // order of the BLS12-381 scalar field, public inputs must be reduced
+    uint256 constant R = 0x{{.R}};
+
+    uint256 constant NB_PUBLIC_INPUTS = {{.NbPublicInputs}};
+
+    // points in the EIP-2537 encoding: 64 bytes per Fp element, G1 = x|y, G2 = x.c0|x.c1|y.c0|y.c1
+    bytes constant ALPHA = hex"{{.Alpha}}";
+    bytes constant NEG_BETA = hex"{{.NegBeta}}";
+    bytes constant NEG_GAMMA = hex"{{.NegGamma}}";
+    bytes constant NEG_DELTA = hex"{{.NegDelta}}";
+    // NB_PUBLIC_INPUTS + 1 G1 points, IC[0] goes with the constant 1
+    bytes constant IC = hex"{{.IC}}";
+
+    /// @param proof A|B|C in the EIP-2537 encoding, 512 bytes
+    /// @param input the public inputs, without the constant 1
+    function verifyProof(bytes calldata proof, uint256[] calldata input) external view returns (bool) {
+        if (proof.length != 512 || input.length != NB_PUBLIC_INPUTS) {
+            return false;
+        }
+
+        // vk_x with a single G1MSM call, every IC point is followed by its 32 bytes scalar
+        bytes memory ic = IC;
+        bytes memory msmInput = new bytes((NB_PUBLIC_INPUTS + 1) * 160);
+        for (uint256 i = 0; i <= NB_PUBLIC_INPUTS; i++) {
+            uint256 scalar = i == 0 ? 1 : input[i - 1];
+            if (scalar >= R) {
+                return false;
+            }
+            assembly {
+                let dst := add(add(msmInput, 32), mul(i, 160))
+                let src := add(add(ic, 32), mul(i, 128))
+                mstore(dst, mload(src))
+                mstore(add(dst, 32), mload(add(src, 32)))
+                mstore(add(dst, 64), mload(add(src, 64)))
+                mstore(add(dst, 96), mload(add(src, 96)))
+                mstore(add(dst, 128), scalar)
+            }
+        }
+        (bool ok, bytes memory vkX) = G1_MSM.staticcall(msmInput);
+        if (!ok || vkX.length != 128) {
+            return false;
+        }
+
+        // the precompile fails on points that are not on the curve or not in the subgroup
+        bytes memory ab = proof[0:384];
+        bytes memory c = proof[384:512];
+        bytes memory result;
+        (ok, result) = PAIRING_CHECK.staticcall(abi.encodePacked(ab, ALPHA, NEG_BETA, vkX, NEG_GAMMA, c, NEG_DELTA));
+        return ok && result.length == 32 && uint256(bytes32(result)) == 1;
+    }
+}
+`))
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Values is a vector of scalars as written in r1cs.json and witness.json: every value is a JSON number or a
+// decimal/hex ("0x...") string, and may be negative. They don't belong to a field until a curve is picked,
+// so they are kept as integers and reduced modulo its scalar field order by the curve backends.
+type Values []*big.Int
+
+// ParseValue parses a single JSON number or string, see Values
+func ParseValue(data []byte) (*big.Int, error) {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	// only decimal and 0x hex, base 0 would read "0155" as octal and accept "0b" and "1_55" forms
+	digits, negative := s, false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits, negative = digits[1:], digits[0] == '-'
+	}
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+		digits, base = digits[2:], 16
+	}
+
+	v, ok := new(big.Int), digits != "" && digits[0] != '-' && digits[0] != '+'
+	if ok {
+		_, ok = v.SetString(digits, base)
+	}
+	if !ok {
+		return nil, fmt.Errorf("can't parse into a big.Int: %s, expected a decimal or 0x hex integer", s)
+	}
+	if negative {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func (values *Values) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	res := make(Values, len(raw))
+	for i := range raw {
+		v, err := ParseValue(raw[i])
+		if err != nil {
+			return err
+		}
+		res[i] = v
+	}
+
+	*values = res
+	return nil
+}
+
+// MarshalJSON writes small values as JSON numbers and the others as decimal strings, which JavaScript can't
+// hold in a number
+func (values Values) MarshalJSON() ([]byte, error) {
+	raw := make([]json.RawMessage, len(values))
+	for i, v := range values {
+		raw[i] = json.RawMessage(valueJSON(v))
+	}
+	return json.Marshal(raw)
+}
+
+// Inline returns the JSON array on a single line, e.g. [0, 1, -5], the way rows are laid out in r1cs.json and
+// witness.json
+func (values Values) Inline() string {
+	raw := make([]string, len(values))
+	for i, v := range values {
+		raw[i] = valueJSON(v)
+	}
+	return "[" + strings.Join(raw, ", ") + "]"
+}
+
+func valueJSON(v *big.Int) string {
+	s := v.String()
+	if len(s) > 15 {
+		s = `"` + s + `"`
+	}
+	return s
+}
+
+// Reduce returns the values reduced modulo the field order, in [0, modulus)
+func Reduce(values []*big.Int, modulus *big.Int) []*big.Int {
+	res := make([]*big.Int, len(values))
+	for i, v := range values {
+		res[i] = new(big.Int).Mod(v, modulus)
+	}
+	return res
+}
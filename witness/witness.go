@@ -3,63 +3,112 @@ package witness
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"gitlab.com/oelmekki/matrix"
+	"io"
+	"os"
+	"math/big"
+	"r1cs-zk-go/utils"
 )
 
+// WitnessData holds the witness vector split in its public and private parts. Like the R1CS
+// coefficients, values are parsed from numbers or decimal/hex strings and reduced mod r once the curve is known.
 type WitnessData struct {
-	PublicInputs  []int `json:"publicInputs"`
-	PrivateInputs []int `json:"privateInputs"`
+	PublicInputs  utils.Values `json:"publicInputs"`
+	PrivateInputs utils.Values `json:"privateInputs"`
 }
 
 type PublicWitnessData struct {
-	PublicInputs []int `json:"publicInputs"`
+	PublicInputs utils.Values `json:"publicInputs"`
 }
 
 type PrivateWitnessData struct {
-	PrivateInputs []int `json:"privateInputs"`
+	PrivateInputs utils.Values `json:"privateInputs"`
 }
 
-func LoadWitnessFromJSON() (matrix.Matrix, int, error) {
-	// Read the JSON file
-	jsonData, err := ioutil.ReadFile("witness.json")
+// LoadWitnessFromJSON reads and parses the witness JSON file at path
+func LoadWitnessFromJSON(path string) (WitnessData, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return matrix.Matrix{}, 0, fmt.Errorf("failed to read witness file: %v", err)
+		return WitnessData{}, fmt.Errorf("failed to read witness file: %v", err)
 	}
+	defer f.Close()
 
+	return ReadWitness(f)
+}
+
+// ReadWitness parses a witness JSON document from r
+func ReadWitness(r io.Reader) (WitnessData, error) {
 	var witnessData WitnessData
-	err = json.Unmarshal(jsonData, &witnessData)
+	err := json.NewDecoder(r).Decode(&witnessData)
 	if err != nil {
-		return matrix.Matrix{}, 0, fmt.Errorf("failed to parse witness JSON: %v", err)
+		return WitnessData{}, fmt.Errorf("failed to parse witness JSON: %v", err)
 	}
-	publicInputsSize := len(witnessData.PublicInputs)
-	// Combine public and private inputs: [publicInputs..., privateInputs...]
-	combined := append(witnessData.PublicInputs, witnessData.PrivateInputs...)
-	
-	builder := make(matrix.Builder, len(combined))
-	for i, val := range combined {
-		builder[i] = matrix.Row{float64(val)}
+
+	return witnessData, nil
+}
+
+// ReadInputs parses the values of the named inputs of a circuit, a JSON object such as {"x": 5, "y": "0x9b"},
+// from which a solver computes the witness
+func ReadInputs(r io.Reader) (map[string]*big.Int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse inputs JSON: %v", err)
 	}
 
-	witness, err := matrix.Build(builder)
-	if err != nil {
-		return matrix.Matrix{}, 0, fmt.Errorf("failed to build witness matrix: %v", err)
+	inputs := make(map[string]*big.Int, len(raw))
+	for name, data := range raw {
+		value, err := utils.ParseValue(data)
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %v", name, err)
+		}
+		inputs[name] = value
 	}
+	return inputs, nil
+}
 
-	return witness, publicInputsSize, nil
+// Values returns the full witness vector: [publicInputs..., privateInputs...]
+func (witnessData WitnessData) Values() []*big.Int {
+	combined := make([]*big.Int, 0, len(witnessData.PublicInputs) + len(witnessData.PrivateInputs))
+	combined = append(combined, witnessData.PublicInputs...)
+	return append(combined, witnessData.PrivateInputs...)
 }
 
-func LoadPublicInputsFromJSON() ([]int, error) {
-	jsonData, err := ioutil.ReadFile("witness.json")
+// LoadPublicInputsFromJSON reads the public inputs written by the prover next to the proof, 'public.json' by default.
+// The verifier never needs the prover's witness.json.
+func LoadPublicInputsFromJSON(path string) ([]*big.Int, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read witness file: %v", err)
+		return nil, fmt.Errorf("failed to read public inputs file: %v", err)
 	}
+	defer f.Close()
+
+	return ReadPublicInputs(f)
+}
 
+// ReadPublicInputs parses the public inputs JSON document written by WritePublicInputs
+func ReadPublicInputs(r io.Reader) ([]*big.Int, error) {
 	var publicWitnessData PublicWitnessData
-	err = json.Unmarshal(jsonData, &publicWitnessData)
+	err := json.NewDecoder(r).Decode(&publicWitnessData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse witness JSON: %v", err)
+		return nil, fmt.Errorf("failed to parse public inputs JSON: %v", err)
 	}
 
 	return publicWitnessData.PublicInputs, nil
 }
+
+// WritePublicInputs writes the public part of the witness to w as JSON
+func WritePublicInputs(w io.Writer, publicInputs []*big.Int) error {
+	jsonData, err := json.MarshalIndent(PublicWitnessData{PublicInputs: publicInputs}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal public inputs: %v", err)
+	}
+
+	_, err = w.Write(jsonData)
+	return err
+}
+
+// WriteWitness writes the witness to w as JSON, with each part on a single line like the example witness.json
+func WriteWitness(w io.Writer, witnessData WitnessData) error {
+	_, err := fmt.Fprintf(w, "{\n  \"publicInputs\": %s,\n  \"privateInputs\": %s\n}\n",
+		witnessData.PublicInputs.Inline(), witnessData.PrivateInputs.Inline())
+	return err
+}